@@ -2,19 +2,28 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/cloud-wave-best-zizon/order-service/internal/catalog"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
 	"github.com/cloud-wave-best-zizon/order-service/internal/events"
 	"github.com/cloud-wave-best-zizon/order-service/internal/handler"
+	"github.com/cloud-wave-best-zizon/order-service/internal/idgen"
+	"github.com/cloud-wave-best-zizon/order-service/internal/outbox"
 	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/order-service/internal/saga"
 	"github.com/cloud-wave-best-zizon/order-service/internal/service"
+	"github.com/cloud-wave-best-zizon/order-service/internal/sweeper"
 	"github.com/cloud-wave-best-zizon/order-service/pkg/config"
+	"github.com/cloud-wave-best-zizon/order-service/pkg/cursor"
 	"github.com/cloud-wave-best-zizon/order-service/pkg/middleware"
 	pkgtls "github.com/cloud-wave-best-zizon/order-service/pkg/tls"
 	"crypto/tls"
@@ -24,6 +33,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// orderStore - 서비스, 아웃박스 릴레이, 사가 오케스트레이터, 스위퍼가 함께 쓰는 저장소
+type orderStore interface {
+	service.OrderStore
+	outbox.Store
+	saga.Store
+	sweeper.Store
+}
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
@@ -37,44 +54,341 @@ func main() {
 		log.Fatal("Failed to load config:", err)
 	}
 
+	if err := domain.SetDefaultCurrency(cfg.DefaultCurrency); err != nil {
+		log.Fatal("Invalid DEFAULT_CURRENCY:", err)
+	}
+	events.SetSource(cfg.CloudEventsSource)
+
+	// order-service ensure-schema - 주문 테이블/GSI/TTL을 만들고 종료
+	if len(os.Args) > 1 && os.Args[1] == "ensure-schema" {
+		dynamoClient, err := repository.NewDynamoDBClient(cfg)
+		if err != nil {
+			log.Fatal("Failed to create DynamoDB client:", err)
+		}
+		if err := repository.EnsureSchema(context.Background(), dynamoClient, cfg.OrderTableName, logger); err != nil {
+			logger.Fatal("Failed to ensure DynamoDB schema", zap.Error(err))
+		}
+		logger.Info("DynamoDB schema is up to date", zap.String("table", cfg.OrderTableName))
+		return
+	}
+
+	// order-service backfill-status-index - GSI2(상태별 인덱스) 도입 전에 만들어진 주문에 인덱스 키를 채우고 종료
+	if len(os.Args) > 1 && os.Args[1] == "backfill-status-index" {
+		dynamoClient, err := repository.NewDynamoDBClient(cfg)
+		if err != nil {
+			log.Fatal("Failed to create DynamoDB client:", err)
+		}
+		updated, err := repository.NewOrderRepository(dynamoClient, cfg.OrderTableName).BackfillStatusIndex(context.Background())
+		if err != nil {
+			logger.Fatal("Failed to backfill status index", zap.Int("updated", updated), zap.Error(err))
+		}
+		logger.Info("Status index backfilled", zap.String("table", cfg.OrderTableName), zap.Int("updated", updated))
+		return
+	}
+
+	// order-service rebuild-projection <주문 ID>... - 이벤트 스트림(ORDER_PERSISTENCE=events)을 재생해 주문의 METADATA 프로젝션을 다시 쓰고 종료
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-projection" {
+		if len(os.Args) < 3 {
+			log.Fatal("Usage: order-service rebuild-projection <order-id>...")
+		}
+		dynamoClient, err := repository.NewDynamoDBClient(cfg)
+		if err != nil {
+			log.Fatal("Failed to create DynamoDB client:", err)
+		}
+		eventRepo := repository.NewEventSourcedOrderRepository(
+			repository.NewOrderRepository(dynamoClient, cfg.OrderTableName), cfg.OrderSnapshotInterval)
+		failed := 0
+		for _, arg := range os.Args[2:] {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				logger.Error("Invalid order id", zap.String("order_id", arg))
+				failed++
+				continue
+			}
+			order, err := eventRepo.RebuildProjection(context.Background(), id)
+			if err != nil {
+				logger.Error("Failed to rebuild order projection", zap.Int("order_id", id), zap.Error(err))
+				failed++
+				continue
+			}
+			logger.Info("Order projection rebuilt",
+				zap.Int("order_id", id),
+				zap.Int("version", order.Version),
+				zap.String("status", string(order.Status)))
+		}
+		if failed > 0 {
+			logger.Fatal("Some order projections were not rebuilt", zap.Int("failed", failed))
+		}
+		return
+	}
+
+	if err := cfg.RequireNodeID(); err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
 	tlsConfig := &pkgtls.TLSConfig{}
 	if err := envconfig.Process("", tlsConfig); err != nil {
 		logger.Fatal("Failed to load TLS config", zap.Error(err))
 	}
+	defer pkgtls.Cleanup()
 
 	logger.Info("Service configuration",
 		zap.String("port", cfg.Port),
+		zap.String("storage_backend", cfg.StorageBackend),
+		zap.String("event_backend", cfg.EventBackend),
+		zap.String("order_persistence", cfg.OrderPersistence),
 		zap.String("kafka_brokers", cfg.KafkaBrokers),
+		zap.Bool("kafka_tls", cfg.KafkaTLSEnabled),
+		zap.String("kafka_sasl", cfg.KafkaSASLMechanism),
+		zap.String("dynamodb_endpoint", cfg.DynamoDBEndpoint),
+		zap.Int("node_id", cfg.NodeID),
 		zap.Bool("tls_enabled", tlsConfig.Enabled),
 		zap.Bool("internal_tls", os.Getenv("INTERNAL_TLS_ENABLED") == "true"))
 
 	// Initialize components
-	dynamoClient, err := repository.NewDynamoDBClient(cfg)
-	if err != nil {
-		log.Fatal("Failed to create DynamoDB client:", err)
+	var orderRepo orderStore
+	switch cfg.StorageBackend {
+	case config.StorageBackendMemory:
+		logger.Warn("Using in-memory storage, all data is lost on restart")
+		orderRepo = repository.NewMemoryOrderRepository()
+	case config.StorageBackendPostgres:
+		pool, err := repository.NewPostgresPool(context.Background(), cfg.PostgresDSN, cfg.PostgresMaxConns)
+		if err != nil {
+			log.Fatal("Failed to connect to PostgreSQL:", err)
+		}
+		defer pool.Close()
+
+		pgRepo := repository.NewPostgresOrderRepository(pool)
+		if cfg.PostgresMigrate {
+			if err := pgRepo.Migrate(context.Background()); err != nil {
+				log.Fatal("Failed to apply PostgreSQL migrations:", err)
+			}
+		}
+		orderRepo = pgRepo
+	default:
+		dynamoClient, err := repository.NewDynamoDBClient(cfg)
+		if err != nil {
+			log.Fatal("Failed to create DynamoDB client:", err)
+		}
+		if cfg.DynamoDBAutoCreate {
+			if err := repository.EnsureSchema(context.Background(), dynamoClient, cfg.OrderTableName, logger); err != nil {
+				log.Fatal("Failed to ensure DynamoDB schema:", err)
+			}
+		}
+		baseRepo := repository.NewOrderRepository(dynamoClient, cfg.OrderTableName)
+		if cfg.OrderPersistence == config.OrderPersistenceEvents {
+			orderRepo = repository.NewEventSourcedOrderRepository(baseRepo, cfg.OrderSnapshotInterval)
+		} else {
+			orderRepo = baseRepo
+		}
 	}
 
-	kafkaProducer, err := events.NewKafkaProducer(cfg.KafkaBrokers, logger)
+	var orderPublisher, compensationPublisher events.EventPublisher
+	var kafkaConn events.KafkaConnection
+	var kafkaHealth *events.KafkaHealthChecker
+	switch cfg.EventBackend {
+	case config.EventBackendMemory:
+		logger.Warn("Using in-memory event publisher, events are not delivered to other services")
+		orderPublisher = events.NewMemoryPublisher(events.TopicOrderEvents, logger)
+		compensationPublisher = events.NewMemoryPublisher(events.TopicCompensationEvents, logger)
+	default:
+		brokers, err := events.ParseBrokers(cfg.KafkaBrokers)
+		if err != nil {
+			log.Fatal("Invalid KAFKA_BROKERS:", err)
+		}
+		kafkaConn.Brokers = brokers
+		if kafkaConn.SASL, err = events.NewSASLMechanism(cfg.KafkaSASLMechanism, cfg.KafkaSASLUsername, cfg.KafkaSASLPassword); err != nil {
+			log.Fatal("Failed to configure Kafka SASL:", err)
+		}
+		if cfg.KafkaTLSEnabled {
+			kafkaConn.TLS, err = events.NewKafkaTLSConfig(events.KafkaTLSOptions{
+				CAFile:             cfg.KafkaTLSCAFile,
+				CertFile:           cfg.KafkaTLSCertFile,
+				KeyFile:            cfg.KafkaTLSKeyFile,
+				ServerName:         cfg.KafkaTLSServerName,
+				InsecureSkipVerify: cfg.KafkaTLSInsecureSkipVerify,
+			})
+			if err != nil {
+				log.Fatal("Failed to configure Kafka TLS:", err)
+			}
+			// 브로커 mTLS에 SPIRE가 발급한 X.509 SVID를 클라이언트 인증서로 사용
+			if cfg.KafkaTLSSPIFFE {
+				if kafkaConn.TLS.GetClientCertificate, err = pkgtls.ClientCertificateFunc(tlsConfig, logger); err != nil {
+					log.Fatal("Failed to load SPIFFE SVID for Kafka:", err)
+				}
+			}
+		} else if cfg.KafkaSASLMechanism == config.KafkaSASLPlain {
+			logger.Warn("Kafka SASL/PLAIN without TLS sends credentials in clear text")
+		}
+
+		codec, err := events.NewCodec(cfg.EventCodec)
+		if err != nil {
+			log.Fatal("Failed to create event codec:", err)
+		}
+		keying, err := events.ParseKeying(cfg.KafkaKeyStrategy, cfg.KafkaKeyOverrides)
+		if err != nil {
+			log.Fatal("Invalid Kafka key strategy:", err)
+		}
+		serialization := events.Serialization{
+			Mode:   events.ContentMode(cfg.EventContentMode),
+			Codec:  codec,
+			Keying: keying,
+		}
+		switch {
+		case cfg.SchemaRegistryURL != "":
+			serialization.Registry, err = events.NewHTTPSchemaRegistry(cfg.SchemaRegistryURL, cfg.SchemaRegistryTimeout)
+		case cfg.SchemaRegistryDir != "":
+			serialization.Registry, err = events.NewFileSchemaRegistry(cfg.SchemaRegistryDir)
+		}
+		if err != nil {
+			log.Fatal("Failed to create schema registry client:", err)
+		}
+
+		kafkaHealth = events.NewKafkaHealthChecker(kafkaConn,
+			[]string{events.TopicOrderEvents, events.TopicCompensationEvents},
+			cfg.KafkaHealthCacheTTL, cfg.KafkaHealthTimeout)
+
+		kafkaProducer, err := events.NewKafkaProducer(kafkaConn, serialization, kafkaHealth, logger)
+		if err != nil {
+			log.Fatal("Failed to create Kafka producer:", err)
+		}
+		orderPublisher = kafkaProducer
+
+		compensationProducer, err := events.NewCompensationProducer(kafkaConn, serialization, kafkaHealth, logger)
+		if err != nil {
+			log.Fatal("Failed to create compensation producer:", err)
+		}
+		compensationPublisher = compensationProducer
+	}
+	defer orderPublisher.Close()
+	defer compensationPublisher.Close()
+
+	relay := outbox.NewRelay(orderRepo, outbox.Config{
+		PollInterval: cfg.OutboxPollInterval,
+		BatchSize:    cfg.OutboxBatchSize,
+		BaseBackoff:  cfg.OutboxBaseBackoff,
+		MaxBackoff:   cfg.OutboxMaxBackoff,
+	}, logger)
+	relay.Register(events.TopicOrderEvents, orderPublisher)
+	relay.Register(events.TopicCompensationEvents, compensationPublisher)
+
+	// Background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		relay.Run(workerCtx)
+	}()
+
+	idGen, err := idgen.NewSnowflake(cfg.NodeID)
 	if err != nil {
-		log.Fatal("Failed to create Kafka producer:", err)
+		log.Fatal("Failed to create order ID generator:", err)
 	}
-	defer kafkaProducer.Close()
 
-	orderRepo := repository.NewOrderRepository(dynamoClient, cfg.OrderTableName)
-	orderService := service.NewOrderService(orderRepo, kafkaProducer, logger)
-	orderHandler := handler.NewOrderHandler(orderService, logger)
+	var priceCatalog catalog.PriceCatalog
+	switch cfg.PriceCatalog {
+	case config.PriceCatalogStatic:
+		staticCatalog, err := catalog.LoadStaticCatalog(cfg.PriceCatalogFile)
+		if err != nil {
+			log.Fatal("Failed to load price catalog:", err)
+		}
+		priceCatalog = staticCatalog
+	default:
+		priceCatalog = catalog.NewHTTPCatalog(cfg.ProductServiceURL, cfg.PriceCatalogTimeout)
+	}
+
+	orderService := service.NewOrderService(orderRepo, relay, idGen, priceCatalog, service.Config{
+		IdempotencyTTL:  cfg.IdempotencyTTL,
+		SagaStepTimeout: cfg.SagaStepTimeout,
+	}, logger)
+	var cursors *cursor.Signer
+	if cfg.CursorSecret != "" {
+		cursors = cursor.NewSigner([]byte(cfg.CursorSecret))
+	} else {
+		logger.Warn("CURSOR_SECRET is not set, pagination cursors will not survive restarts")
+		if cursors, err = cursor.NewRandomSigner(); err != nil {
+			log.Fatal("Failed to create cursor signer:", err)
+		}
+	}
+
+	// Saga orchestrator - 상품별 재고 차감 추적, 실패/타임아웃/주문 취소 시 보상
+	orchestrator := saga.NewOrchestrator(orderRepo, orderService, relay, saga.Config{
+		StepTimeout:  cfg.SagaStepTimeout,
+		PollInterval: cfg.SagaPollInterval,
+	}, logger)
+
+	orderHandler := handler.NewOrderHandler(orderService, orchestrator, cursors, logger)
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		orchestrator.Run(workerCtx)
+	}()
+
+	// Stale order sweeper - 재고 처리 결과가 오지 않은 PENDING 주문 재발행/취소
+	if cfg.SweeperEnabled {
+		orderSweeper := sweeper.NewSweeper(orderRepo, orderService, orchestrator, sweeper.Config{
+			Interval:     cfg.SweeperInterval,
+			Deadline:     cfg.SweeperDeadline,
+			Policy:       sweeper.Policy(cfg.SweeperPolicy),
+			BatchSize:    cfg.SweeperBatchSize,
+			LeaseTTL:     cfg.SweeperLeaseTTL,
+			MaxRepublish: cfg.SweeperMaxRepublish,
+		}, logger)
+
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			orderSweeper.Run(workerCtx)
+		}()
+	}
+
+	// Stock result consumer - 재고 차감 결과를 사가에 전달
+	var stockConsumer *events.KafkaConsumer
+	if cfg.KafkaConsumerEnabled && cfg.EventBackend == config.EventBackendKafka {
+		stockConsumer, err = events.NewKafkaConsumer(kafkaConn, cfg.KafkaGroupID,
+			[]string{cfg.StockEventsTopic},
+			events.StockResultHandler(orchestrator.HandleStockResult), logger)
+		if err != nil {
+			log.Fatal("Failed to create Kafka consumer:", err)
+		}
+
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			defer stockConsumer.Close()
+			if err := stockConsumer.Run(workerCtx); err != nil {
+				logger.Error("Kafka consumer failed", zap.Error(err))
+			}
+		}()
+	}
 
 	// Setup Gin Router
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logger(logger))
 	router.Use(middleware.RequestID())
+	router.Use(middleware.Principal())
 
 	// Routes
 	v1 := router.Group("/api/v1")
 	{
 		v1.POST("/orders", orderHandler.CreateOrder)
 		v1.GET("/orders/:id", orderHandler.GetOrder)
+		v1.GET("/orders/:id/history", orderHandler.GetOrderHistory)
+		v1.PATCH("/orders/:id", orderHandler.AmendOrder)
+		v1.POST("/orders/:id/transitions", orderHandler.TransitionOrder)
+		v1.POST("/orders/:id/cancel", orderHandler.CancelOrder)
+		v1.POST("/orders/:id/lines/cancel", orderHandler.CancelOrderLines)
+		v1.GET("/users/:user_id/orders", orderHandler.ListUserOrders)
+		// 운영 API - ADMIN_PRINCIPALS에 등록된 mTLS 클라이언트만 (ALB 포트 요청은 401)
+		requireAdmin := middleware.RequirePrincipal(cfg.AdminPrincipals)
+		admin := v1.Group("/admin", requireAdmin)
+		admin.GET("/orders", orderHandler.AdminListOrders)
+		// 워커 메트릭 (order_sweeper 등, expvar JSON) - 커맨드라인과 메모리 통계가 노출되므로 운영 API와 같은 권한
+		v1.GET("/debug/vars", requireAdmin, gin.WrapH(expvar.Handler()))
 		v1.GET("/health", func(c *gin.Context) {
 			status := gin.H{
 				"status":  "healthy",
@@ -83,13 +397,40 @@ func main() {
 				"tls":     tlsConfig.Enabled,
 				"internal_tls": os.Getenv("INTERNAL_TLS_ENABLED") == "true",
 			}
-			if err := kafkaProducer.HealthCheck(); err != nil {
+			// 재고 결과 컨슈머가 메시지를 가져오지 못하거나 멈췄으면 503 (사가가 진행되지 않음)
+			code := 200
+			if stockConsumer != nil {
+				if err := stockConsumer.HealthCheck(); err != nil {
+					status["stock_consumer"] = "unhealthy"
+					status["stock_consumer_error"] = err.Error()
+					code = 503
+				} else {
+					status["stock_consumer"] = "healthy"
+				}
+			}
+			// Kafka는 브로커별 연결과 토픽 메타데이터까지 보고 (일부 브로커만 실패하면 degraded, 200)
+			if kafkaHealth != nil {
+				health := kafkaHealth.Check(c.Request.Context())
+				status["kafka"] = health.Status
+				status["kafka_brokers"] = health.Brokers
+				status["kafka_topics"] = health.Topics
+				status["kafka_checked_at"] = health.CheckedAt
+				if health.Status == events.KafkaUnhealthy {
+					c.JSON(503, status)
+					return
+				}
+				c.JSON(code, status)
+				return
+			}
+			// Kafka 점검기가 없으면 아웃박스 릴레이에 등록된 모든 발행기(토픽별) 상태
+			if err := relay.HealthCheck(); err != nil {
 				status["kafka"] = "unhealthy"
+				status["kafka_error"] = err.Error()
 				c.JSON(503, status)
 				return
 			}
 			status["kafka"] = "healthy"
-			c.JSON(200, status)
+			c.JSON(code, status)
 		})
 	}
 
@@ -160,4 +501,8 @@ func main() {
 	
 	wg.Wait()
 	logger.Info("All servers stopped")
+
+	stopWorkers()
+	workers.Wait()
+	logger.Info("Background workers stopped")
 }
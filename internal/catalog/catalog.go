@@ -0,0 +1,58 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+var (
+	ErrProductNotFound    = errors.New("product not found in catalog")
+	ErrCatalogUnavailable = errors.New("price catalog unavailable")
+)
+
+// Price - 카탈로그 기준 상품 단가
+type Price struct {
+	ProductID string
+	Name      string
+	Price     domain.Money
+	Version   string
+}
+
+// Quote - 한 번의 조회로 얻은 상품별 단가와 그 기준 카탈로그 버전
+type Quote struct {
+	Version string
+	Prices  map[string]Price
+}
+
+// PriceCatalog - 주문 금액 계산에 쓰는 권위 있는 가격 조회
+type PriceCatalog interface {
+	// GetPrices - 없는 상품이 있으면 ErrProductNotFound를 감싼 에러 반환
+	GetPrices(ctx context.Context, productIDs []string) (*Quote, error)
+}
+
+// combinedVersion - 상품별 버전이 모두 같으면 그 값, 다르면 "상품=버전" 목록
+func combinedVersion(prices map[string]Price) string {
+	ids := make([]string, 0, len(prices))
+	for id := range prices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	same := true
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		v := prices[id].Version
+		if v != prices[ids[0]].Version {
+			same = false
+		}
+		parts = append(parts, id+"="+v)
+	}
+	if same && len(ids) > 0 {
+		return prices[ids[0]].Version
+	}
+	return strings.Join(parts, ";")
+}
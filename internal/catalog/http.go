@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+// HTTPCatalog - Product Service의 상품 조회 API로 가격 확인
+type HTTPCatalog struct {
+	baseURL string
+	client  *http.Client
+}
+
+type productResponse struct {
+	ProductID string      `json:"product_id"`
+	Name      string      `json:"name"`
+	Price     json.Number `json:"price"`
+	// Product Service가 제공하는 경우에만 사용
+	Currency  string      `json:"currency"`
+	Version   json.Number `json:"version"`
+	UpdatedAt string      `json:"updated_at"`
+}
+
+func NewHTTPCatalog(baseURL string, timeout time.Duration) *HTTPCatalog {
+	return &HTTPCatalog{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *HTTPCatalog) GetPrices(ctx context.Context, productIDs []string) (*Quote, error) {
+	prices := make(map[string]Price, len(productIDs))
+	for _, id := range productIDs {
+		if _, ok := prices[id]; ok {
+			continue
+		}
+		p, err := c.getPrice(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		prices[id] = *p
+	}
+	return &Quote{Version: combinedVersion(prices), Prices: prices}, nil
+}
+
+func (c *HTTPCatalog) getPrice(ctx context.Context, productID string) (*Price, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.baseURL+"/api/v1/products/"+url.PathEscape(productID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCatalogUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrProductNotFound, productID)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: product service returned %d for %s", ErrCatalogUnavailable, resp.StatusCode, productID)
+	}
+
+	var body productResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: invalid product response for %s: %v", ErrCatalogUnavailable, productID, err)
+	}
+
+	currency := body.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency()
+	}
+	price, err := domain.ParseMoney(body.Price.String(), currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid price for %s: %v", ErrCatalogUnavailable, productID, err)
+	}
+
+	// 버전 우선순위: 응답 헤더 > version 필드 > updated_at > ETag
+	version := resp.Header.Get("X-Catalog-Version")
+	switch {
+	case version != "":
+	case body.Version != "":
+		version = body.Version.String()
+	case body.UpdatedAt != "":
+		version = body.UpdatedAt
+	default:
+		version = strings.Trim(resp.Header.Get("ETag"), `"`)
+	}
+
+	return &Price{
+		ProductID: productID,
+		Name:      body.Name,
+		Price:     price,
+		Version:   version,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+// StaticCatalog - JSON 파일에서 읽은 고정 가격표 (로컬 개발용)
+//
+//	{"version": "2025-08-01", "currency": "KRW", "products": {"PROD001": {"name": "MacBook Pro", "price": "2690000"}}}
+type StaticCatalog struct {
+	version string
+	prices  map[string]Price
+}
+
+type staticFile struct {
+	Version string `json:"version"`
+	// 생략하면 기본 통화
+	Currency string `json:"currency"`
+	Products map[string]struct {
+		Name  string      `json:"name"`
+		Price json.Number `json:"price"`
+	} `json:"products"`
+}
+
+func LoadStaticCatalog(path string) (*StaticCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price catalog: %w", err)
+	}
+
+	var f staticFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse price catalog %s: %w", path, err)
+	}
+	if f.Version == "" {
+		return nil, fmt.Errorf("price catalog %s has no version", path)
+	}
+
+	c := &StaticCatalog{
+		version: f.Version,
+		prices:  make(map[string]Price, len(f.Products)),
+	}
+	if f.Currency == "" {
+		f.Currency = domain.DefaultCurrency()
+	}
+	for id, p := range f.Products {
+		price, err := domain.ParseMoney(p.Price.String(), f.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price for %s in %s: %w", id, path, err)
+		}
+		c.prices[id] = Price{ProductID: id, Name: p.Name, Price: price, Version: f.Version}
+	}
+	return c, nil
+}
+
+func (c *StaticCatalog) GetPrices(ctx context.Context, productIDs []string) (*Quote, error) {
+	quote := &Quote{Version: c.version, Prices: make(map[string]Price, len(productIDs))}
+	for _, id := range productIDs {
+		p, ok := c.prices[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrProductNotFound, id)
+		}
+		quote.Prices[id] = p
+	}
+	return quote, nil
+}
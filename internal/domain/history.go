@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type HistoryAction string
+
+const (
+	HistoryActionCreated        HistoryAction = "CREATED"
+	HistoryActionStatusChanged  HistoryAction = "STATUS_CHANGED"
+	HistoryActionLinesCancelled HistoryAction = "LINES_CANCELLED"
+	HistoryActionAmended        HistoryAction = "AMENDED"
+)
+
+// Actor - 변경을 요청한 주체와 요청 정보 (내부 워커는 ID만 채움)
+// ID는 인증된 주체만, 클라이언트가 주장한 값(X-Actor-ID 등)은 검증되지 않은 ClaimedID에 기록
+type Actor struct {
+	ID        string `json:"id"`
+	ClaimedID string `json:"claimed_id,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	SourceIP  string `json:"source_ip,omitempty"`
+}
+
+// FieldChange - 변경된 주문 필드 하나의 이전/이후 값 (JSON)
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
+
+// HistoryEntry - 주문 변경 이력 한 건 (추가만 하고 수정/삭제하지 않음)
+type HistoryEntry struct {
+	OrderID   int           `json:"order_id"`
+	Version   int           `json:"version"` // 변경 후 주문 버전
+	Action    HistoryAction `json:"action"`
+	Actor     Actor         `json:"actor"`
+	OldStatus OrderStatus   `json:"old_status,omitempty"`
+	NewStatus OrderStatus   `json:"new_status"`
+	Diff      []FieldChange `json:"diff,omitempty"`
+	Reason    string        `json:"reason,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+type OrderHistoryResponse struct {
+	OrderID int             `json:"order_id"`
+	History []*HistoryEntry `json:"history"`
+}
@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrAmountOverflow   = errors.New("amount overflow")
+)
+
+// currencyExponents - ISO-4217 통화별 최소 단위 소수 자릿수
+var currencyExponents = map[string]int{
+	"KRW": 0, "JPY": 0, "VND": 0,
+	"USD": 2, "EUR": 2, "GBP": 2, "CNY": 2, "HKD": 2, "SGD": 2, "TWD": 2, "AUD": 2, "CAD": 2,
+	"KWD": 3, "BHD": 3,
+}
+
+// defaultCurrency - 통화 없이 들어온 금액(기존 float 데이터, 숫자만 보낸 요청)에 적용
+var defaultCurrency = "KRW"
+
+func DefaultCurrency() string {
+	return defaultCurrency
+}
+
+// SetDefaultCurrency - 시작 시 한 번만 호출
+func SetDefaultCurrency(code string) error {
+	if _, err := CurrencyExponent(code); err != nil {
+		return err
+	}
+	defaultCurrency = code
+	return nil
+}
+
+func CurrencyExponent(code string) (int, error) {
+	exp, ok := currencyExponents[code]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return exp, nil
+}
+
+// Money - 최소 단위 정수 금액과 ISO-4217 통화 (KRW 1원, USD 1센트)
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, currency string) (Money, error) {
+	if _, err := CurrencyExponent(currency); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// ParseMoney - "12.34" 같은 10진 문자열을 정확히 변환, 통화 자릿수를 넘는 소수는 거부
+func ParseMoney(amount, currency string) (Money, error) {
+	return parseMoney(amount, currency, false)
+}
+
+// ParseLegacyMoney - float로 저장된 기존 금액을 기본 통화로 변환, 통화 자릿수를 넘는 소수는 반올림
+func ParseLegacyMoney(amount string) (Money, error) {
+	return parseMoney(amount, defaultCurrency, true)
+}
+
+// parseMoney - round가 true면 통화 자릿수를 넘는 소수를 반올림 (기존 float 데이터용)
+func parseMoney(amount, currency string, round bool) (Money, error) {
+	exp, err := CurrencyExponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	// big.Rat은 "1/3" 같은 분수도 받으므로 10진 표기만 허용
+	if strings.Contains(amount, "/") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)))
+
+	minor := new(big.Int)
+	if r.IsInt() {
+		minor.Set(r.Num())
+	} else if round {
+		// 0.5 단위는 0에서 먼 쪽으로 반올림
+		half := big.NewRat(1, 2)
+		if r.Sign() < 0 {
+			half.Neg(half)
+		}
+		r.Add(r, half)
+		minor.Quo(r.Num(), r.Denom())
+	} else {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places for %s", ErrInvalidAmount, amount, exp, currency)
+	}
+
+	if !minor.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q", ErrAmountOverflow, amount)
+	}
+	return Money{Amount: minor.Int64(), Currency: currency}, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add - 통화가 다르거나 int64 범위를 넘으면 에러
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	if (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) ||
+		(o.Amount < 0 && m.Amount < math.MinInt64-o.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Mul - 단가 × 수량
+func (m Money) Mul(n int64) (Money, error) {
+	if n != 0 && m.Amount != 0 {
+		p := m.Amount * n
+		if p/n != m.Amount || (n == -1 && m.Amount == math.MinInt64) {
+			return Money{}, ErrAmountOverflow
+		}
+		return Money{Amount: p, Currency: m.Currency}, nil
+	}
+	return Money{Currency: m.Currency}, nil
+}
+
+// Decimal - 통화 자릿수에 맞춘 10진 문자열 ("12.34")
+func (m Money) Decimal() string {
+	exp := currencyExponents[m.Currency]
+	neg := m.Amount < 0
+	digits := strconv.FormatUint(absInt64(m.Amount), 10)
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if neg {
+		return "-" + digits
+	}
+	return digits
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func absInt64(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
+
+type moneyJSON struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON - 부동소수점 손실이 없도록 금액은 문자열로 전달
+//
+//	{"amount": "12.34", "currency": "USD"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.Currency})
+}
+
+// UnmarshalJSON - 객체 형식 외에 숫자만 있는 기존 형식(기본 통화)도 허용
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		var v moneyJSON
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if v.Currency == "" {
+			v.Currency = defaultCurrency
+		}
+		parsed, err := ParseMoney(v.Amount.String(), v.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
+	}
+	parsed, err := ParseMoney(n.String(), defaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+		err      error
+	}{
+		{"1000", "KRW", 1000, nil},
+		{"1000.0", "KRW", 1000, nil},
+		{"10.5", "KRW", 0, ErrInvalidAmount},
+		{"12.34", "USD", 1234, nil},
+		{"12.3", "USD", 1230, nil},
+		{"0.01", "USD", 1, nil},
+		{"12.345", "USD", 0, ErrInvalidAmount},
+		{"1.234", "KWD", 1234, nil},
+		{"-12.34", "USD", -1234, nil},
+		{"1e3", "KRW", 1000, nil},
+		{"1/3", "USD", 0, ErrInvalidAmount},
+		{"abc", "USD", 0, ErrInvalidAmount},
+		{"", "USD", 0, ErrInvalidAmount},
+		{"100", "XYZ", 0, ErrUnknownCurrency},
+		{"9223372036854775807", "KRW", math.MaxInt64, nil},
+		{"9223372036854775808", "KRW", 0, ErrAmountOverflow},
+		{"92233720368547758.08", "USD", 0, ErrAmountOverflow},
+		{"-9223372036854775809", "KRW", 0, ErrAmountOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.currency)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Amount != tt.want || got.Currency != tt.currency {
+				t.Errorf("got %+v, want %d %s", got, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestParseLegacyMoneyRounds(t *testing.T) {
+	tests := []struct {
+		currency string
+		amount   string
+		want     int64
+	}{
+		{"KRW", "1500", 1500},
+		{"KRW", "1499.5", 1500},
+		{"KRW", "1499.4999", 1499},
+		{"KRW", "-0.5", -1},
+		{"KRW", "-1499.4", -1499},
+		{"USD", "12.345", 1235},
+		// float64로 저장되며 생긴 오차
+		{"USD", "0.30000000000000004", 30},
+		{"USD", "19.989999999999998", 1999},
+	}
+	defer SetDefaultCurrency(DefaultCurrency())
+	for _, tt := range tests {
+		t.Run(tt.currency+" "+tt.amount, func(t *testing.T) {
+			if err := SetDefaultCurrency(tt.currency); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseLegacyMoney(tt.amount)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Amount != tt.want || got.Currency != tt.currency {
+				t.Errorf("got %+v, want %d %s", got, tt.want, tt.currency)
+			}
+		})
+	}
+
+	if _, err := ParseLegacyMoney("1e19"); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("overflow err = %v, want ErrAmountOverflow", err)
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{Amount: 1500, Currency: "KRW"}, "1500"},
+		{Money{Amount: 1234, Currency: "USD"}, "12.34"},
+		{Money{Amount: 5, Currency: "USD"}, "0.05"},
+		{Money{Amount: -5, Currency: "USD"}, "-0.05"},
+		{Money{Amount: 1, Currency: "KWD"}, "0.001"},
+		{Money{Amount: 0, Currency: "EUR"}, "0.00"},
+		{Money{Amount: math.MinInt64, Currency: "KRW"}, "-9223372036854775808"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("%d %s Decimal() = %q, want %q", tt.money.Amount, tt.money.Currency, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	for _, m := range []Money{
+		{Amount: 1500, Currency: "KRW"},
+		{Amount: 1234, Currency: "USD"},
+		{Amount: -5, Currency: "USD"},
+		{Amount: 1234, Currency: "KWD"},
+		{Amount: math.MaxInt64, Currency: "KRW"},
+	} {
+		b, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Money
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", b, err)
+		}
+		if got != m {
+			t.Errorf("%s round-tripped to %+v, want %+v", b, got, m)
+		}
+	}
+
+	if b, _ := json.Marshal(Money{Amount: 1234, Currency: "USD"}); string(b) != `{"amount":"12.34","currency":"USD"}` {
+		t.Errorf("marshalled %s", b)
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  error
+	}{
+		{`{"amount": "12.34", "currency": "USD"}`, Money{Amount: 1234, Currency: "USD"}, nil},
+		{`{"amount": 12.34, "currency": "USD"}`, Money{Amount: 1234, Currency: "USD"}, nil},
+		// 통화가 없으면 기본 통화, 숫자만 있으면 기존 형식
+		{`{"amount": "1500"}`, Money{Amount: 1500, Currency: "KRW"}, nil},
+		{`1500`, Money{Amount: 1500, Currency: "KRW"}, nil},
+		{`"1500"`, Money{Amount: 1500, Currency: "KRW"}, nil},
+		{`"abc"`, Money{}, ErrInvalidAmount},
+		{`{"amount": "12.345", "currency": "USD"}`, Money{}, ErrInvalidAmount},
+		{`{"amount": "1", "currency": "XYZ"}`, Money{}, ErrUnknownCurrency},
+		{`{"amount": "-3.5", "currency": "EUR"}`, Money{Amount: -350, Currency: "EUR"}, nil},
+		{`99999999999999999999`, Money{}, ErrAmountOverflow},
+		{`null`, Money{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmeticOverflow(t *testing.T) {
+	max := Money{Amount: math.MaxInt64, Currency: "KRW"}
+	min := Money{Amount: math.MinInt64, Currency: "KRW"}
+	one := Money{Amount: 1, Currency: "KRW"}
+
+	if _, err := max.Add(one); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("max + 1 err = %v, want ErrAmountOverflow", err)
+	}
+	if _, err := min.Add(Money{Amount: -1, Currency: "KRW"}); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("min - 1 err = %v, want ErrAmountOverflow", err)
+	}
+	if _, err := one.Add(Money{Amount: 1, Currency: "USD"}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("KRW + USD err = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := max.Mul(2); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("max * 2 err = %v, want ErrAmountOverflow", err)
+	}
+	if _, err := min.Mul(-1); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("min * -1 err = %v, want ErrAmountOverflow", err)
+	}
+	if got, err := (Money{Amount: -1500, Currency: "KRW"}).Mul(3); err != nil || got.Amount != -4500 {
+		t.Errorf("-1500 * 3 = %+v, %v", got, err)
+	}
+	if got, err := max.Mul(0); err != nil || !got.IsZero() || got.Currency != "KRW" {
+		t.Errorf("max * 0 = %+v, %v", got, err)
+	}
+}
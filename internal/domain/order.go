@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -9,30 +10,128 @@ type OrderStatus string
 const (
 	OrderStatusPending   OrderStatus = "PENDING"
 	OrderStatusConfirmed OrderStatus = "CONFIRMED"
+	OrderStatusShipped   OrderStatus = "SHIPPED"
+	OrderStatusDelivered OrderStatus = "DELIVERED"
 	OrderStatusCancelled OrderStatus = "CANCELLED"
 )
 
+func (s OrderStatus) Valid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusConfirmed, OrderStatusShipped,
+		OrderStatusDelivered, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
 type Order struct {
-	OrderID        int         `json:"order_id"`
-	UserID         string      `json:"user_id"`
-	Items          []OrderItem `json:"items"`
-	TotalAmount    float64     `json:"total_amount"`
-	Status         OrderStatus `json:"status"`
-	IdempotencyKey string      `json:"idempotency_key"`
-	CreatedAt      time.Time   `json:"created_at"`
-	UpdatedAt      time.Time   `json:"updated_at"`
+	OrderID        int          `json:"order_id"`
+	UserID         string       `json:"user_id"`
+	Items          []OrderItem  `json:"items"`
+	TotalAmount    Money        `json:"total_amount"`
+	Status         OrderStatus  `json:"status"`
+	IdempotencyKey string       `json:"idempotency_key"`
+	CatalogVersion string       `json:"catalog_version,omitempty"` // 단가 산정에 사용한 가격 카탈로그 버전
+	LineHistory    []LineChange `json:"line_history,omitempty"`    // 항목별 부분 취소 이력
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	// 낙관적 동시성 제어용, 주문이 바뀔 때마다 1씩 증가 (HTTP ETag)
+	Version int `json:"version"`
 }
 
+type LineStatus string
+
+const (
+	LineStatusActive             LineStatus = "ACTIVE"
+	LineStatusPartiallyCancelled LineStatus = "PARTIALLY_CANCELLED"
+	LineStatusCancelled          LineStatus = "CANCELLED"
+)
+
+// OrderItem - Quantity는 주문(항목 변경 반영) 수량, 부분 취소된 수량은 CancelledQuantity로 따로 관리
 type OrderItem struct {
-	ProductID   string  `json:"product_id"`
-	ProductName string  `json:"product_name"`
-	Quantity    int     `json:"quantity"`
-	Price       float64 `json:"price"`
+	LineID            int        `json:"line_id"`
+	ProductID         string     `json:"product_id"`
+	ProductName       string     `json:"product_name"`
+	Quantity          int        `json:"quantity" binding:"required,min=1"` // 요청 검증용 (저장된 주문은 삭제된 항목이 0)
+	Price             Money      `json:"price"`
+	Status            LineStatus `json:"status,omitempty"`
+	CancelledQuantity int        `json:"cancelled_quantity,omitempty"`
+}
+
+// ActiveQuantity - 취소되지 않고 남은 수량
+func (i OrderItem) ActiveQuantity() int {
+	return i.Quantity - i.CancelledQuantity
+}
+
+// LineChange - 주문 항목 변경 이력 한 건
+type LineChange struct {
+	LineID    int        `json:"line_id"`
+	ProductID string     `json:"product_id"`
+	Action    LineAction `json:"action,omitempty"`   // 비어 있으면 CANCEL
+	Quantity  int        `json:"quantity,omitempty"` // CANCEL - 이번 변경으로 취소된 수량
+	// ADD/CHANGE/REMOVE - 변경 전후 주문 수량
+	FromQuantity int        `json:"from_quantity,omitempty"`
+	ToQuantity   int        `json:"to_quantity,omitempty"`
+	FromStatus   LineStatus `json:"from_status,omitempty"`
+	ToStatus     LineStatus `json:"to_status"`
+	Reason       string     `json:"reason,omitempty"`
+	ChangedAt    time.Time  `json:"changed_at"`
+}
+
+type LineAction string
+
+const (
+	LineActionCancel LineAction = "CANCEL"
+	LineActionAdd    LineAction = "ADD"
+	LineActionChange LineAction = "CHANGE"
+	LineActionRemove LineAction = "REMOVE"
+)
+
+// EnsureLineIDs - 항목 ID가 생기기 전에 저장된 주문은 순서대로 ID 부여
+func (o *Order) EnsureLineIDs() {
+	for i := range o.Items {
+		if o.Items[i].LineID == 0 {
+			o.Items[i].LineID = i + 1
+		}
+		if o.Items[i].Status == "" {
+			o.Items[i].Status = LineStatusActive
+		}
+	}
+}
+
+// Line - lineID에 해당하는 항목 (없으면 nil)
+func (o *Order) Line(lineID int) *OrderItem {
+	for i := range o.Items {
+		if o.Items[i].LineID == lineID {
+			return &o.Items[i]
+		}
+	}
+	return nil
+}
+
+// Recalculate - 남은 수량 기준으로 TotalAmount 재계산
+func (o *Order) Recalculate() error {
+	if len(o.Items) == 0 {
+		o.TotalAmount = Money{Currency: o.TotalAmount.Currency}
+		return nil
+	}
+	total := Money{Currency: o.Items[0].Price.Currency}
+	for _, item := range o.Items {
+		subtotal, err := item.Price.Mul(int64(item.ActiveQuantity()))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", item.LineID, err)
+		}
+		if total, err = total.Add(subtotal); err != nil {
+			return fmt.Errorf("line %d: %w", item.LineID, err)
+		}
+	}
+	o.TotalAmount = total
+	return nil
 }
 
 type CreateOrderRequest struct {
 	UserID         string      `json:"user_id" binding:"required"`
-	Items          []OrderItem `json:"items" binding:"required,min=1"`
+	Items          []OrderItem `json:"items" binding:"required,min=1,dive"`
 	IdempotencyKey string      `json:"idempotency_key" binding:"required"`
 }
 
@@ -42,11 +141,73 @@ type CreateOrderResponse struct {
 	Message string      `json:"message"`
 }
 
+type TransitionOrderRequest struct {
+	Status OrderStatus `json:"status" binding:"required"`
+	Reason string      `json:"reason"`
+}
+
+// CancelReasonCode - 주문 취소 사유 코드
+type CancelReasonCode string
+
+const (
+	CancelReasonCustomerRequest CancelReasonCode = "CUSTOMER_REQUEST"
+	CancelReasonOutOfStock      CancelReasonCode = "OUT_OF_STOCK"
+	CancelReasonPaymentFailed   CancelReasonCode = "PAYMENT_FAILED"
+	CancelReasonFraudSuspected  CancelReasonCode = "FRAUD_SUSPECTED"
+	CancelReasonOther           CancelReasonCode = "OTHER"
+)
+
+func (c CancelReasonCode) Valid() bool {
+	switch c {
+	case CancelReasonCustomerRequest, CancelReasonOutOfStock, CancelReasonPaymentFailed,
+		CancelReasonFraudSuspected, CancelReasonOther:
+		return true
+	}
+	return false
+}
+
+type CancelOrderRequest struct {
+	ReasonCode CancelReasonCode `json:"reason_code" binding:"required"`
+	Reason     string           `json:"reason"` // 선택 상세 설명
+}
+
+// CancelLinesRequest - 항목별 부분 취소 (같은 line_id가 여러 번 오면 수량을 합산)
+type CancelLinesRequest struct {
+	Lines      []CancelLine     `json:"lines" binding:"required,min=1,dive"`
+	ReasonCode CancelReasonCode `json:"reason_code" binding:"required"`
+	Reason     string           `json:"reason"`
+}
+
+type CancelLine struct {
+	LineID   int `json:"line_id" binding:"required"`
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+// AmendOrderRequest - PENDING 주문의 항목 변경 (If-Match 헤더로 버전 지정)
+type AmendOrderRequest struct {
+	Changes []ItemChange `json:"changes" binding:"required,min=1,dive"`
+	Reason  string       `json:"reason"`
+}
+
+// ItemChange - ADD: product_id, quantity, price / CHANGE: line_id, quantity / REMOVE: line_id
+type ItemChange struct {
+	Op        LineAction `json:"op" binding:"required"`
+	LineID    int        `json:"line_id"`
+	ProductID string     `json:"product_id"`
+	Quantity  int        `json:"quantity"`
+	Price     Money      `json:"price"`
+}
+
 type GetOrderResponse struct {
 	OrderID     int         `json:"order_id"`
 	UserID      string      `json:"user_id"`
 	Items       []OrderItem `json:"items"`
-	TotalAmount float64     `json:"total_amount"`
+	TotalAmount Money       `json:"total_amount"`
 	Status      OrderStatus `json:"status"`
 	CreatedAt   time.Time   `json:"created_at"`
 }
+
+type ListOrdersResponse struct {
+	Orders     []*Order `json:"orders"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrEventSequence - 이벤트 순번이 주문 버전과 이어지지 않음 (누락되었거나 중복된 이벤트)
+var ErrEventSequence = errors.New("order event out of sequence")
+
+// OrderEventType - 이벤트 소싱 저장소에 기록되는 주문 도메인 이벤트 (Kafka 이벤트와 별개)
+type OrderEventType string
+
+const (
+	OrderEventCreated       OrderEventType = "ORDER_CREATED"
+	OrderEventItemsChanged  OrderEventType = "ORDER_ITEMS_CHANGED"
+	OrderEventConfirmed     OrderEventType = "ORDER_CONFIRMED"
+	OrderEventCancelled     OrderEventType = "ORDER_CANCELLED"
+	OrderEventStatusChanged OrderEventType = "ORDER_STATUS_CHANGED" // 배송 등 그 밖의 상태 변경
+)
+
+// OrderEvent - 주문 이벤트 스트림의 한 건, Sequence는 이벤트 적용 후의 주문 Version과 같음
+type OrderEvent struct {
+	OrderID   int             `json:"order_id"`
+	Sequence  int             `json:"sequence"`
+	Type      OrderEventType  `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// ItemsChangedData - 부분 취소/항목 변경 후의 항목, 총액, 항목 이력, 가격 카탈로그 버전
+type ItemsChangedData struct {
+	Items       []OrderItem  `json:"items"`
+	TotalAmount Money        `json:"total_amount"`
+	LineHistory []LineChange `json:"line_history,omitempty"`
+	// 필드가 추가되기 전에 기록된 이벤트에는 없으므로 비어 있으면 이전 값을 유지
+	CatalogVersion string    `json:"catalog_version,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// StatusChangedData - 상태 변경 (확정, 취소, 배송 등)
+type StatusChangedData struct {
+	From      OrderStatus `json:"from"`
+	To        OrderStatus `json:"to"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// NewOrderCreatedEvent - 생성된 주문 전체를 담은 첫 이벤트
+func NewOrderCreatedEvent(order *Order) (OrderEvent, error) {
+	return newOrderEvent(order.OrderID, order.Version, OrderEventCreated, order, order.CreatedAt)
+}
+
+// NewItemsChangedEvent - 변경 후 주문의 항목 상태를 담은 이벤트 (order.Version은 변경 후 버전)
+func NewItemsChangedEvent(order *Order) (OrderEvent, error) {
+	return newOrderEvent(order.OrderID, order.Version, OrderEventItemsChanged, ItemsChangedData{
+		Items:          order.Items,
+		TotalAmount:    order.TotalAmount,
+		LineHistory:    order.LineHistory,
+		CatalogVersion: order.CatalogVersion,
+		UpdatedAt:      order.UpdatedAt,
+	}, order.UpdatedAt)
+}
+
+// NewStatusChangedEvent - 변경 후 상태에 따라 확정/취소/상태 변경 이벤트 생성
+func NewStatusChangedEvent(orderID, sequence int, from, to OrderStatus, updatedAt time.Time) (OrderEvent, error) {
+	eventType := OrderEventStatusChanged
+	switch to {
+	case OrderStatusConfirmed:
+		eventType = OrderEventConfirmed
+	case OrderStatusCancelled:
+		eventType = OrderEventCancelled
+	}
+	return newOrderEvent(orderID, sequence, eventType, StatusChangedData{From: from, To: to, UpdatedAt: updatedAt}, updatedAt)
+}
+
+func newOrderEvent(orderID, sequence int, eventType OrderEventType, data any, ts time.Time) (OrderEvent, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return OrderEvent{}, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	return OrderEvent{OrderID: orderID, Sequence: sequence, Type: eventType, Data: b, Timestamp: ts}, nil
+}
+
+// Apply - 이벤트 하나를 주문에 반영, 순번은 현재 Version 바로 다음이어야 함
+func (o *Order) Apply(e OrderEvent) error {
+	if e.Sequence != o.Version+1 {
+		return fmt.Errorf("%w: order %d is at version %d, got event %d", ErrEventSequence, o.OrderID, o.Version, e.Sequence)
+	}
+
+	switch e.Type {
+	case OrderEventCreated:
+		var created Order
+		if err := json.Unmarshal(e.Data, &created); err != nil {
+			return fmt.Errorf("failed to decode %s event: %w", e.Type, err)
+		}
+		*o = created
+
+	case OrderEventItemsChanged:
+		var data ItemsChangedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return fmt.Errorf("failed to decode %s event: %w", e.Type, err)
+		}
+		o.Items = data.Items
+		o.TotalAmount = data.TotalAmount
+		o.LineHistory = data.LineHistory
+		if data.CatalogVersion != "" {
+			o.CatalogVersion = data.CatalogVersion
+		}
+		o.UpdatedAt = data.UpdatedAt
+
+	case OrderEventConfirmed, OrderEventCancelled, OrderEventStatusChanged:
+		var data StatusChangedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return fmt.Errorf("failed to decode %s event: %w", e.Type, err)
+		}
+		if o.Status != data.From {
+			return fmt.Errorf("%w: order %d is %s, event expects %s", ErrEventSequence, o.OrderID, o.Status, data.From)
+		}
+		o.Status = data.To
+		o.UpdatedAt = data.UpdatedAt
+
+	default:
+		return fmt.Errorf("unknown order event type %q", e.Type)
+	}
+
+	o.Version = e.Sequence
+	return nil
+}
+
+// ReplayOrder - 스냅샷(없으면 nil)에 이후 이벤트를 순서대로 적용해 주문 복원
+func ReplayOrder(snapshot *Order, events []OrderEvent) (*Order, error) {
+	order := &Order{}
+	if snapshot != nil {
+		*order = *snapshot
+	}
+	for _, e := range events {
+		if err := order.Apply(e); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
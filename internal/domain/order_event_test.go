@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// orderStream - 생성 → 항목 변경 → 확정 순서의 이벤트와 각 이벤트 적용 후 주문
+func orderStream(t *testing.T) ([]OrderEvent, []*Order) {
+	t.Helper()
+	now := time.Now().UTC()
+	order := &Order{
+		OrderID: 7,
+		UserID:  "user-1",
+		Items: []OrderItem{
+			{LineID: 1, ProductID: "PROD-A", Quantity: 2, Price: Money{Amount: 1500, Currency: "KRW"}, Status: LineStatusActive},
+			{LineID: 2, ProductID: "PROD-B", Quantity: 1, Price: Money{Amount: 3000, Currency: "KRW"}, Status: LineStatusActive},
+		},
+		Status:         OrderStatusPending,
+		CatalogVersion: "v1",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Version:        1,
+	}
+	if err := order.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	var events []OrderEvent
+	var states []*Order
+	record := func(e OrderEvent, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		snapshot := *order
+		snapshot.Items = append([]OrderItem(nil), order.Items...)
+		snapshot.LineHistory = append([]LineChange(nil), order.LineHistory...)
+		events = append(events, e)
+		states = append(states, &snapshot)
+	}
+	record(NewOrderCreatedEvent(order))
+
+	// 항목 변경 - PROD-A 1개 부분 취소, 변경 시점의 가격 카탈로그로 다시 산정
+	order.Items[0].CancelledQuantity = 1
+	order.LineHistory = append(order.LineHistory, LineChange{
+		LineID: 1, ProductID: "PROD-A", Action: LineActionCancel,
+		FromQuantity: 2, ToQuantity: 1, FromStatus: LineStatusActive, ToStatus: LineStatusActive,
+		ChangedAt: now.Add(time.Second),
+	})
+	if err := order.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	order.CatalogVersion = "v2"
+	order.UpdatedAt = now.Add(time.Second)
+	order.Version = 2
+	record(NewItemsChangedEvent(order))
+
+	// 확정
+	order.Status = OrderStatusConfirmed
+	order.UpdatedAt = now.Add(2 * time.Second)
+	order.Version = 3
+	record(NewStatusChangedEvent(order.OrderID, 3, OrderStatusPending, OrderStatusConfirmed, order.UpdatedAt))
+
+	return events, states
+}
+
+// roundTrip - 저장소에 기록했다가 읽은 것처럼 JSON으로 왕복
+func roundTrip(t *testing.T, order *Order) *Order {
+	t.Helper()
+	b, err := json.Marshal(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out Order
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	return &out
+}
+
+func assertSameOrder(t *testing.T, got, want *Order) {
+	t.Helper()
+	g, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := json.Marshal(roundTrip(t, want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(g) != string(w) {
+		t.Errorf("replayed order\n got %s\nwant %s", g, w)
+	}
+}
+
+func TestReplayOrderAcrossSnapshot(t *testing.T) {
+	events, states := orderStream(t)
+	projection := states[len(states)-1]
+
+	// 스냅샷 없이 처음부터
+	full, err := ReplayOrder(nil, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameOrder(t, full, projection)
+
+	// 항목 변경 뒤(버전 2)의 스냅샷 + 확정 이벤트
+	snapshot := roundTrip(t, states[1])
+	fromSnapshot, err := ReplayOrder(snapshot, events[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameOrder(t, fromSnapshot, projection)
+	if fromSnapshot.Status != OrderStatusConfirmed || fromSnapshot.Version != 3 {
+		t.Errorf("status %s version %d, want CONFIRMED 3", fromSnapshot.Status, fromSnapshot.Version)
+	}
+	if got := fromSnapshot.Items[0].ActiveQuantity(); got != 1 {
+		t.Errorf("PROD-A active quantity = %d, want 1", got)
+	}
+	if full.CatalogVersion != "v2" {
+		t.Errorf("catalog version = %q, want v2 from the items changed event", full.CatalogVersion)
+	}
+}
+
+func TestApplyItemsChangedWithoutCatalogVersion(t *testing.T) {
+	events, _ := orderStream(t)
+
+	// catalog_version 필드가 추가되기 전에 기록된 항목 변경 이벤트
+	var data ItemsChangedData
+	if err := json.Unmarshal(events[1].Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	data.CatalogVersion = ""
+	legacy := events[1]
+	b, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy.Data = b
+
+	order, err := ReplayOrder(nil, []OrderEvent{events[0], legacy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.CatalogVersion != "v1" {
+		t.Errorf("catalog version = %q, want v1 kept from the created event", order.CatalogVersion)
+	}
+}
+
+func TestReplayOrderRejectsEventsOutOfSequence(t *testing.T) {
+	events, states := orderStream(t)
+
+	// 스냅샷에 이미 반영된 이벤트를 다시 적용
+	if _, err := ReplayOrder(roundTrip(t, states[1]), events[1:]); !errors.Is(err, ErrEventSequence) {
+		t.Errorf("duplicate event err = %v, want ErrEventSequence", err)
+	}
+	// 항목 변경 이벤트 누락
+	if _, err := ReplayOrder(nil, []OrderEvent{events[0], events[2]}); !errors.Is(err, ErrEventSequence) {
+		t.Errorf("missing event err = %v, want ErrEventSequence", err)
+	}
+}
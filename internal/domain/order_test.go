@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestCreateOrderRequestValidation(t *testing.T) {
+	valid := func() CreateOrderRequest {
+		return CreateOrderRequest{
+			UserID:         "user-1",
+			Items:          []OrderItem{{ProductID: "PROD-A", Quantity: 2}},
+			IdempotencyKey: "key-1",
+		}
+	}
+	if err := binding.Validator.ValidateStruct(valid()); err != nil {
+		t.Fatalf("valid request rejected: %v", err)
+	}
+
+	cases := map[string]func(*CreateOrderRequest){
+		"no items":          func(r *CreateOrderRequest) { r.Items = nil },
+		"zero quantity":     func(r *CreateOrderRequest) { r.Items[0].Quantity = 0 },
+		"negative quantity": func(r *CreateOrderRequest) { r.Items[0].Quantity = -1 },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := valid()
+			mutate(&req)
+			if err := binding.Validator.ValidateStruct(req); err == nil {
+				t.Error("request accepted, want validation error")
+			}
+		})
+	}
+}
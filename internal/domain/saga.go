@@ -0,0 +1,103 @@
+package domain
+
+import "time"
+
+type SagaStatus string
+
+const (
+	SagaStatusRunning   SagaStatus = "RUNNING"
+	SagaStatusCompleted SagaStatus = "COMPLETED"
+	SagaStatusAborted   SagaStatus = "ABORTED"
+)
+
+type SagaStepStatus string
+
+const (
+	SagaStepPending     SagaStepStatus = "PENDING"
+	SagaStepCompleted   SagaStepStatus = "COMPLETED"
+	SagaStepFailed      SagaStepStatus = "FAILED"
+	SagaStepTimedOut    SagaStepStatus = "TIMED_OUT"
+	SagaStepCompensated SagaStepStatus = "COMPENSATED"
+)
+
+// SagaStep - 상품별 재고 차감 단계
+type SagaStep struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	// 부분 취소된 수량 (차감이 완료되면 이만큼 보상)
+	CancelledQuantity int `json:"cancelled_quantity,omitempty"`
+	// 지금까지 보상 이벤트를 발행한 수량
+	CompensatedQuantity int            `json:"compensated_quantity,omitempty"`
+	Status              SagaStepStatus `json:"status"`
+	Reason              string         `json:"reason,omitempty"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// Saga - 주문 하나의 재고 차감 진행 상태
+type Saga struct {
+	OrderID int        `json:"order_id"`
+	UserID  string     `json:"user_id"`
+	Status  SagaStatus `json:"status"`
+	Steps   []SagaStep `json:"steps"`
+	// RUNNING 상태에서 이 시각까지 끝나지 않은 단계는 타임아웃 처리
+	Deadline time.Time `json:"deadline"`
+	// 최종 결과가 주문 상태에 반영되었는지 여부
+	OrderUpdated bool      `json:"order_updated"`
+	Version      int       `json:"version"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Step - productID에 해당하는 단계 (없으면 nil)
+func (s *Saga) Step(productID string) *SagaStep {
+	for i := range s.Steps {
+		if s.Steps[i].ProductID == productID {
+			return &s.Steps[i]
+		}
+	}
+	return nil
+}
+
+// NewSaga - 주문의 항목으로 상품별 재고 차감 단계를 만든 진행 중 사가
+// 같은 상품의 여러 항목은 한 단계로 합치고, 항목 변경으로 삭제된(수량 0) 항목은 제외
+func NewSaga(order *Order, deadline, now time.Time) *Saga {
+	saga := &Saga{
+		OrderID:   order.OrderID,
+		UserID:    order.UserID,
+		Status:    SagaStatusRunning,
+		Deadline:  deadline,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, item := range order.Items {
+		if item.Quantity == 0 {
+			continue
+		}
+		if step := saga.Step(item.ProductID); step != nil {
+			step.Quantity += item.Quantity
+			step.CancelledQuantity += item.CancelledQuantity
+			continue
+		}
+		saga.Steps = append(saga.Steps, SagaStep{
+			ProductID:         item.ProductID,
+			Quantity:          item.Quantity,
+			CancelledQuantity: item.CancelledQuantity,
+			Status:            SagaStepPending,
+			UpdatedAt:         now,
+		})
+	}
+	return saga
+}
+
+// AwaitingResults - 진행 중이고 아직 어떤 단계도 재고 차감 결과를 받지 않은 사가
+func (s *Saga) AwaitingResults() bool {
+	if s.Status != SagaStatusRunning {
+		return false
+	}
+	for _, step := range s.Steps {
+		if step.Status != SagaStepPending {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,235 @@
+package events
+
+import (
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// CloudEvents 1.0 (https://github.com/cloudevents/spec) - 발행하는 모든 이벤트의 봉투
+const (
+    CloudEventsSpecVersion = "1.0"
+    CloudEventsContentType = "application/cloudevents+json"
+    dataContentTypeJSON    = "application/json"
+
+    // Product Service 등 다른 서비스와 겹치지 않도록 type 앞에 붙이는 접두사
+    cloudEventTypePrefix = "com.cloudwave.order."
+)
+
+// ContentMode - Kafka 메시지에 CloudEvent를 싣는 방식
+type ContentMode string
+
+const (
+    // ContentModeStructured - 봉투 전체를 JSON 값으로 (content-type: application/cloudevents+json)
+    ContentModeStructured ContentMode = "structured"
+    // ContentModeBinary - 속성은 ce_* 헤더로, 값에는 data만
+    ContentModeBinary ContentMode = "binary"
+)
+
+// cloudEventSource - 이 서비스가 발행하는 이벤트의 source (SetSource로 변경)
+var cloudEventSource = "/order-service"
+
+// SetSource - CloudEvents source 속성 설정 (기동 시 한 번)
+func SetSource(source string) {
+    if source != "" {
+        cloudEventSource = source
+    }
+}
+
+// CloudEvent - structured 모드 JSON 표현 (schemaversion은 확장 속성)
+// JSON이 아닌 코덱(protobuf/avro)의 data는 data_base64로 전달
+type CloudEvent struct {
+    SpecVersion     string          `json:"specversion"`
+    ID              string          `json:"id"`
+    Source          string          `json:"source"`
+    Type            string          `json:"type"`
+    Subject         string          `json:"subject,omitempty"`
+    Time            time.Time       `json:"time"`
+    DataContentType string          `json:"datacontenttype,omitempty"`
+    DataSchema      string          `json:"dataschema,omitempty"`
+    SchemaVersion   int             `json:"schemaversion"`
+    Data            json.RawMessage `json:"data,omitempty"`
+    DataBase64      []byte          `json:"data_base64,omitempty"`
+}
+
+// EventType - type에서 접두사를 뺀 레지스트리 이벤트 타입 (예: OrderCreated)
+func (e *CloudEvent) EventType() string {
+    if i := strings.LastIndexByte(e.Type, '.'); i >= 0 {
+        return e.Type[i+1:]
+    }
+    return e.Type
+}
+
+// eventMeta - 페이로드에서 id/subject로 쓸 값 (모든 주문 이벤트에 있음)
+type eventMeta struct {
+    EventID string      `json:"event_id"`
+    OrderID json.Number `json:"order_id"`
+}
+
+// NewCloudEvent - 이벤트를 레지스트리의 최신 스키마 버전으로 봉투에 담음
+// id는 페이로드의 event_id (없으면 fallbackID), subject는 order_id
+func NewCloudEvent(eventType, fallbackID string, data interface{}) (*CloudEvent, error) {
+    version := DefaultRegistry.LatestVersion(eventType)
+    if version == 0 {
+        return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+    }
+    payload, err := json.Marshal(data)
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+    }
+    return newCloudEvent(eventType, fallbackID, version, time.Now(), payload), nil
+}
+
+func newCloudEvent(eventType, fallbackID string, version int, at time.Time, payload []byte) *CloudEvent {
+    var meta eventMeta
+    _ = json.Unmarshal(payload, &meta)
+    id := meta.EventID
+    if id == "" {
+        id = fallbackID
+    }
+    return &CloudEvent{
+        SpecVersion:     CloudEventsSpecVersion,
+        ID:              id,
+        Source:          cloudEventSource,
+        Type:            cloudEventTypePrefix + eventType,
+        Subject:         meta.OrderID.String(),
+        Time:            at.UTC(),
+        DataContentType: dataContentTypeJSON,
+        SchemaVersion:   version,
+        Data:            payload,
+    }
+}
+
+// WrapLegacy - 봉투 도입 전에 아웃박스에 기록된 JSON을 스키마 버전 1로 감쌈 (이미 봉투면 그대로)
+func WrapLegacy(eventType, fallbackID string, createdAt time.Time, payload []byte) ([]byte, error) {
+    if isStructuredCloudEvent(payload) {
+        return payload, nil
+    }
+    return json.Marshal(newCloudEvent(eventType, fallbackID, 1, createdAt, payload))
+}
+
+func isStructuredCloudEvent(payload []byte) bool {
+    var probe struct {
+        SpecVersion string `json:"specversion"`
+    }
+    return json.Unmarshal(payload, &probe) == nil && probe.SpecVersion != ""
+}
+
+// structuredMessage - 값에 봉투 JSON 전체
+func structuredMessage(key string, value []byte) kafka.Message {
+    return kafka.Message{
+        Key:     []byte(key),
+        Value:   value,
+        Headers: []kafka.Header{{Key: "content-type", Value: []byte(CloudEventsContentType)}},
+    }
+}
+
+// binaryMessage - 봉투 속성은 ce_* 헤더로, 값에는 data
+func binaryMessage(key string, ce *CloudEvent, data []byte) kafka.Message {
+    msg := kafka.Message{Key: []byte(key), Value: data}
+    msg.Headers = []kafka.Header{
+        {Key: "content-type", Value: []byte(ce.DataContentType)},
+        {Key: "ce_specversion", Value: []byte(ce.SpecVersion)},
+        {Key: "ce_id", Value: []byte(ce.ID)},
+        {Key: "ce_source", Value: []byte(ce.Source)},
+        {Key: "ce_type", Value: []byte(ce.Type)},
+        {Key: "ce_time", Value: []byte(ce.Time.Format(time.RFC3339Nano))},
+        {Key: "ce_schemaversion", Value: []byte(strconv.Itoa(ce.SchemaVersion))},
+    }
+    if ce.Subject != "" {
+        msg.Headers = append(msg.Headers, kafka.Header{Key: "ce_subject", Value: []byte(ce.Subject)})
+    }
+    if ce.DataSchema != "" {
+        msg.Headers = append(msg.Headers, kafka.Header{Key: "ce_dataschema", Value: []byte(ce.DataSchema)})
+    }
+    return msg
+}
+
+// FromKafkaMessage - binary/structured 모드 메시지를 CloudEvent로 읽음
+// protobuf/avro data는 datacontenttype의 코덱으로 디코딩해 항상 JSON data로 반환
+// 봉투 없는 기존 JSON 메시지면 nil을 반환하므로 호출자가 페이로드를 직접 해석
+func FromKafkaMessage(msg kafka.Message) (*CloudEvent, error) {
+    ce, err := readCloudEvent(msg)
+    if err != nil || ce == nil {
+        return ce, err
+    }
+    if err := decodeCloudEventData(ce); err != nil {
+        return nil, err
+    }
+    return ce, nil
+}
+
+func readCloudEvent(msg kafka.Message) (*CloudEvent, error) {
+    headers := make(map[string]string, len(msg.Headers))
+    for _, h := range msg.Headers {
+        headers[h.Key] = string(h.Value)
+    }
+
+    if headers["ce_specversion"] != "" {
+        ce := &CloudEvent{
+            SpecVersion:     headers["ce_specversion"],
+            ID:              headers["ce_id"],
+            Source:          headers["ce_source"],
+            Type:            headers["ce_type"],
+            Subject:         headers["ce_subject"],
+            DataContentType: headers["content-type"],
+            DataSchema:      headers["ce_dataschema"],
+            Data:            msg.Value,
+        }
+        if v := headers["ce_time"]; v != "" {
+            t, err := time.Parse(time.RFC3339Nano, v)
+            if err != nil {
+                return nil, fmt.Errorf("invalid ce_time header: %w", err)
+            }
+            ce.Time = t
+        }
+        if v := headers["ce_schemaversion"]; v != "" {
+            version, err := strconv.Atoi(v)
+            if err != nil {
+                return nil, fmt.Errorf("invalid ce_schemaversion header: %w", err)
+            }
+            ce.SchemaVersion = version
+        }
+        return ce, nil
+    }
+
+    if headers["content-type"] == CloudEventsContentType || isStructuredCloudEvent(msg.Value) {
+        var ce CloudEvent
+        if err := json.Unmarshal(msg.Value, &ce); err != nil {
+            return nil, fmt.Errorf("invalid cloudevent payload: %w", err)
+        }
+        return &ce, nil
+    }
+    return nil, nil
+}
+
+func decodeCloudEventData(ce *CloudEvent) error {
+    data := []byte(ce.Data)
+    if len(ce.DataBase64) > 0 {
+        data = ce.DataBase64
+    }
+    codec, err := codecForContentType(ce.DataContentType)
+    if err != nil {
+        return err
+    }
+    if codec == nil {
+        return nil
+    }
+
+    _, data, err = decodeWire(codec.Name(), data)
+    if err != nil {
+        return err
+    }
+    decoded, err := codec.Decode(ce.EventType(), ce.SchemaVersion, data)
+    if err != nil {
+        return err
+    }
+    ce.Data = decoded
+    ce.DataBase64 = nil
+    ce.DataContentType = dataContentTypeJSON
+    return nil
+}
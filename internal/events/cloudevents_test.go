@@ -0,0 +1,247 @@
+package events
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/cloud-wave-best-zizon/order-service/internal/domain"
+    "github.com/segmentio/kafka-go"
+)
+
+func TestNewCloudEventAttributes(t *testing.T) {
+    defer func(source string) { cloudEventSource = source }(cloudEventSource)
+    SetSource("/test/order-service")
+
+    before := time.Now()
+    ce, err := NewCloudEvent(EventTypeOrderCreated, "fallback", OrderCreatedEvent{EventID: "evt-1", OrderID: 42, UserID: "user-1"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := CloudEvent{
+        SpecVersion:     "1.0",
+        ID:              "evt-1",
+        Source:          "/test/order-service",
+        Type:            "com.cloudwave.order.OrderCreated",
+        Subject:         "42",
+        DataContentType: "application/json",
+        SchemaVersion:   2,
+    }
+    got := *ce
+    got.Time, got.Data = time.Time{}, nil
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("envelope = %+v, want %+v", got, want)
+    }
+    if ce.Time.Location() != time.UTC || ce.Time.Before(before.Truncate(time.Second)) {
+        t.Errorf("time = %v, want current time in UTC", ce.Time)
+    }
+    if ce.EventType() != EventTypeOrderCreated {
+        t.Errorf("EventType = %q, want %q", ce.EventType(), EventTypeOrderCreated)
+    }
+
+    // event_id가 없는 이벤트는 fallbackID, 빈 source는 무시
+    SetSource("")
+    ce, err = NewCloudEvent(EventTypeStockDeduction, "fallback", map[string]int{"order_id": 7})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if ce.ID != "fallback" || ce.Subject != "7" || ce.Source != "/test/order-service" || ce.SchemaVersion != 1 {
+        t.Errorf("envelope = %+v, want id fallback, subject 7, schemaversion 1", ce)
+    }
+
+    if _, err := NewCloudEvent("NoSuchEvent", "fallback", struct{}{}); !errors.Is(err, ErrUnknownEventType) {
+        t.Errorf("unknown type err = %v, want ErrUnknownEventType", err)
+    }
+}
+
+func TestCloudEventContentModes(t *testing.T) {
+    at := time.Date(2026, 10, 17, 9, 30, 0, 123456789, time.UTC)
+    event := OrderStatusChangedEvent{EventID: "evt-1", OrderID: 42, UserID: "user-1", FromStatus: "PENDING", ToStatus: "CONFIRMED", Timestamp: at}
+    ce, err := NewCloudEvent(EventTypeOrderStatusChanged, "", event)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ce.Time = at
+    payload, err := json.Marshal(ce)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for _, tt := range []struct {
+        mode    ContentMode
+        headers map[string]string
+    }{
+        {ContentModeStructured, map[string]string{"content-type": CloudEventsContentType}},
+        {ContentModeBinary, map[string]string{
+            "content-type":     "application/json",
+            "ce_specversion":   "1.0",
+            "ce_id":            "evt-1",
+            "ce_source":        ce.Source,
+            "ce_type":          "com.cloudwave.order.OrderStatusChanged",
+            "ce_subject":       "42",
+            "ce_time":          "2026-10-17T09:30:00.123456789Z",
+            "ce_schemaversion": "1",
+        }},
+    } {
+        enc, err := newMessageEncoder(TopicOrderEvents, Serialization{Mode: tt.mode})
+        if err != nil {
+            t.Fatal(err)
+        }
+        msg, err := enc.encode(context.Background(), "42", payload)
+        if err != nil {
+            t.Fatal(err)
+        }
+        headers := make(map[string]string)
+        for _, h := range msg.Headers {
+            headers[h.Key] = string(h.Value)
+        }
+        if !reflect.DeepEqual(headers, tt.headers) {
+            t.Errorf("%s headers = %v, want %v", tt.mode, headers, tt.headers)
+        }
+        if tt.mode == ContentModeBinary && string(msg.Value) != string(ce.Data) {
+            t.Errorf("binary value = %s, want data only %s", msg.Value, ce.Data)
+        }
+
+        got, err := FromKafkaMessage(msg)
+        if err != nil {
+            t.Fatalf("%s FromKafkaMessage: %v", tt.mode, err)
+        }
+        if !got.Time.Equal(at) {
+            t.Errorf("%s time = %v, want %v", tt.mode, got.Time, at)
+        }
+        wantCE := *ce
+        got.Time, wantCE.Time = time.Time{}, time.Time{}
+        var gotData, wantData interface{}
+        if json.Unmarshal(got.Data, &gotData) != nil || json.Unmarshal(wantCE.Data, &wantData) != nil || !reflect.DeepEqual(gotData, wantData) {
+            t.Errorf("%s data = %s, want %s", tt.mode, got.Data, wantCE.Data)
+        }
+        got.Data, wantCE.Data = nil, nil
+        if !reflect.DeepEqual(*got, wantCE) {
+            t.Errorf("%s envelope = %+v, want %+v", tt.mode, *got, wantCE)
+        }
+    }
+
+    // 봉투 없는 기존 JSON 메시지는 nil
+    if got, err := FromKafkaMessage(kafka.Message{Value: []byte(`{"order_id":42}`)}); got != nil || err != nil {
+        t.Errorf("legacy message = %+v, %v, want nil", got, err)
+    }
+    bad := kafka.Message{Headers: []kafka.Header{{Key: "ce_specversion", Value: []byte("1.0")}, {Key: "ce_schemaversion", Value: []byte("two")}}}
+    if _, err := FromKafkaMessage(bad); err == nil {
+        t.Error("invalid ce_schemaversion header accepted")
+    }
+}
+
+func TestWrapLegacy(t *testing.T) {
+    createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+    legacy := []byte(`{"order_id":42,"user_id":"user-1","total_amount":12500}`)
+
+    wrapped, err := WrapLegacy(EventTypeOrderCreated, "msg-1", createdAt, legacy)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var ce CloudEvent
+    if err := json.Unmarshal(wrapped, &ce); err != nil {
+        t.Fatal(err)
+    }
+    // 봉투 도입 전 메시지는 최신 버전이 아니라 v1
+    if ce.ID != "msg-1" || ce.Subject != "42" || ce.SchemaVersion != 1 || !ce.Time.Equal(createdAt) || string(ce.Data) != string(legacy) {
+        t.Errorf("wrapped = %+v, want v1 envelope of the legacy payload", ce)
+    }
+
+    again, err := WrapLegacy(EventTypeOrderCreated, "msg-2", time.Now(), wrapped)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if string(again) != string(wrapped) {
+        t.Errorf("rewrapped envelope = %s, want unchanged %s", again, wrapped)
+    }
+}
+
+func TestDecodeUpcastsOrderCreatedV1(t *testing.T) {
+    at := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+    v1, err := json.Marshal(OrderCreatedEventV1{
+        EventID: "evt-1", OrderID: 42, UserID: "user-1", TotalAmount: 37500,
+        Items: []OrderItemV1{
+            {ProductID: "PROD-A", ProductName: "Keyboard", Quantity: 2, Price: 12500},
+            {ProductID: "PROD-B", ProductName: "Mouse", Quantity: 1, Price: 12500},
+        },
+        Status: "PENDING", Timestamp: at, RequestID: "req-1", IdempotencyKey: "key-1",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    price := domain.Money{Amount: 12500, Currency: domain.DefaultCurrency()}
+    want := &OrderCreatedEvent{
+        EventID: "evt-1", OrderID: 42, UserID: "user-1",
+        TotalAmount: domain.Money{Amount: 37500, Currency: domain.DefaultCurrency()},
+        Items: []domain.OrderItem{
+            {ProductID: "PROD-A", ProductName: "Keyboard", Quantity: 2, Price: price},
+            {ProductID: "PROD-B", ProductName: "Mouse", Quantity: 1, Price: price},
+        },
+        Status: "PENDING", Timestamp: at, RequestID: "req-1", IdempotencyKey: "key-1",
+    }
+
+    // schemaversion이 없는 봉투(0)도 v1로 간주
+    for _, version := range []int{0, 1} {
+        ce := &CloudEvent{Type: cloudEventTypePrefix + EventTypeOrderCreated, SchemaVersion: version, Data: v1}
+        got, err := DefaultRegistry.Decode(ce)
+        if err != nil {
+            t.Fatalf("v%d: %v", version, err)
+        }
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("v%d decoded %+v, want %+v", version, got, want)
+        }
+    }
+
+    // 업캐스트 결과는 v2 그대로 디코딩한 것과 같고, v2에 다시 적용해도 바뀌지 않음
+    upcast, version, err := DefaultRegistry.Upcast(EventTypeOrderCreated, 1, v1)
+    if err != nil || version != 2 {
+        t.Fatalf("Upcast = v%d, %v, want v2", version, err)
+    }
+    twice, err := upcastOrderCreatedV1(upcast)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var a, b interface{}
+    if json.Unmarshal(upcast, &a) != nil || json.Unmarshal(twice, &b) != nil || !reflect.DeepEqual(a, b) {
+        t.Errorf("upcasting v2 data changed it: %s -> %s", upcast, twice)
+    }
+
+    v2, err := json.Marshal(want)
+    if err != nil {
+        t.Fatal(err)
+    }
+    got, err := DefaultRegistry.Decode(&CloudEvent{Type: cloudEventTypePrefix + EventTypeOrderCreated, SchemaVersion: 2, Data: v2})
+    if err != nil || !reflect.DeepEqual(got, want) {
+        t.Errorf("v2 decoded %+v, %v, want %+v", got, err, want)
+    }
+}
+
+func TestDecodeRejectsUnknownVersions(t *testing.T) {
+    data := json.RawMessage(`{"order_id":42}`)
+    for _, tt := range []struct {
+        eventType string
+        version   int
+        want      error
+    }{
+        {EventTypeOrderCreated, 3, ErrUnsupportedSchemaVersion},
+        {EventTypeOrderStatusChanged, 2, ErrUnsupportedSchemaVersion},
+        {"NoSuchEvent", 1, ErrUnknownEventType},
+    } {
+        ce := &CloudEvent{Type: cloudEventTypePrefix + tt.eventType, SchemaVersion: tt.version, Data: data}
+        if _, err := DefaultRegistry.Decode(ce); !errors.Is(err, tt.want) {
+            t.Errorf("%s v%d err = %v, want %v", tt.eventType, tt.version, err, tt.want)
+        }
+    }
+
+    // 업캐스터가 빠진 버전 사이는 변환하지 않음
+    r := NewRegistry()
+    r.Register(EventTypeOrderCreated, 1, OrderCreatedEventV1{})
+    r.Register(EventTypeOrderCreated, 2, OrderCreatedEvent{})
+    if _, _, err := r.Upcast(EventTypeOrderCreated, 1, data); !errors.Is(err, ErrUnsupportedSchemaVersion) {
+        t.Errorf("missing upcaster err = %v, want ErrUnsupportedSchemaVersion", err)
+    }
+}
@@ -0,0 +1,186 @@
+package events
+
+import (
+    "embed"
+    "encoding/json"
+    "fmt"
+    "path"
+    "strings"
+    "sync"
+    "unicode"
+)
+
+// 발행 data 직렬화 방식 (EVENT_CODEC)
+const (
+    CodecJSON     = "json"
+    CodecProtobuf = "protobuf"
+    CodecAvro     = "avro"
+
+    dataContentTypeProtobuf = "application/protobuf"
+    dataContentTypeAvro     = "application/avro"
+)
+
+// 이벤트 타입+스키마 버전별 스키마 파일 (schemas/<형식>/<snake_case 타입>.v<버전>.<확장자>)
+//
+//go:embed schemas/protobuf/*.proto schemas/avro/*.avsc
+var schemaFiles embed.FS
+
+// Codec - CloudEvent data를 바이트로 직렬화 (봉투는 structured/binary 모드가 담당)
+// data는 레지스트리 구조체의 JSON이며, Decode는 같은 JSON으로 되돌림
+type Codec interface {
+    Name() string
+    // ContentType - CloudEvents datacontenttype
+    ContentType() string
+    // Schema - eventType/version의 스키마 (스키마 없는 형식이면 nil)
+    Schema(eventType string, version int) (*Schema, error)
+    Encode(eventType string, version int, data json.RawMessage) ([]byte, error)
+    Decode(eventType string, version int, payload []byte) (json.RawMessage, error)
+}
+
+// NewCodec - json | protobuf | avro (내장 스키마를 모두 파싱하므로 기동 시 한 번)
+func NewCodec(name string) (Codec, error) {
+    switch name {
+    case "", CodecJSON:
+        return jsonCodec{}, nil
+    case CodecProtobuf:
+        return newProtobufCodec()
+    case CodecAvro:
+        return newAvroCodec()
+    default:
+        return nil, fmt.Errorf("unknown event codec %q", name)
+    }
+}
+
+// jsonCodec - 기존 방식, data를 JSON 그대로 전달
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string        { return CodecJSON }
+func (jsonCodec) ContentType() string { return dataContentTypeJSON }
+
+func (jsonCodec) Schema(string, int) (*Schema, error) { return nil, nil }
+
+func (jsonCodec) Encode(_ string, _ int, data json.RawMessage) ([]byte, error) {
+    return data, nil
+}
+
+func (jsonCodec) Decode(_ string, _ int, payload []byte) (json.RawMessage, error) {
+    return payload, nil
+}
+
+var (
+    decodeCodecsOnce sync.Once
+    decodeCodecs     map[string]Codec
+    decodeCodecsErr  error
+)
+
+// codecForContentType - 수신한 datacontenttype에 맞는 코덱 (JSON이면 nil)
+func codecForContentType(contentType string) (Codec, error) {
+    mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+    if mediaType == "" || mediaType == dataContentTypeJSON || strings.HasSuffix(mediaType, "+json") {
+        return nil, nil
+    }
+
+    decodeCodecsOnce.Do(func() {
+        decodeCodecs = make(map[string]Codec)
+        for _, name := range []string{CodecProtobuf, CodecAvro} {
+            codec, err := NewCodec(name)
+            if err != nil {
+                decodeCodecsErr = err
+                return
+            }
+            decodeCodecs[codec.ContentType()] = codec
+        }
+    })
+    if decodeCodecsErr != nil {
+        return nil, decodeCodecsErr
+    }
+    codec, ok := decodeCodecs[mediaType]
+    if !ok {
+        return nil, fmt.Errorf("unsupported datacontenttype %q", contentType)
+    }
+    return codec, nil
+}
+
+type schemaFile struct {
+    key    schemaKey
+    name   string
+    source string
+}
+
+// loadSchemaFiles - dir의 스키마 파일을 이벤트 타입+버전별로 읽음
+// 파일 이름은 레지스트리에 등록된 타입/버전과 일치해야 함
+func loadSchemaFiles(dir, ext string) ([]schemaFile, error) {
+    entries, err := schemaFiles.ReadDir(dir)
+    if err != nil {
+        return nil, err
+    }
+
+    types := make(map[string]string)
+    for eventType := range DefaultRegistry.latestVersions() {
+        types[snakeCase(eventType)] = eventType
+    }
+
+    files := make([]schemaFile, 0, len(entries))
+    for _, entry := range entries {
+        name := entry.Name()
+        base := strings.TrimSuffix(name, ext)
+        var snake string
+        var version int
+        if i := strings.LastIndex(base, ".v"); i > 0 {
+            snake = base[:i]
+            if _, err := fmt.Sscanf(base[i+2:], "%d", &version); err != nil {
+                version = 0
+            }
+        }
+        eventType, ok := types[snake]
+        if !ok || version <= 0 || version > DefaultRegistry.LatestVersion(eventType) {
+            return nil, fmt.Errorf("schema file %s does not match a registered event type/version", name)
+        }
+
+        source, err := schemaFiles.ReadFile(path.Join(dir, name))
+        if err != nil {
+            return nil, err
+        }
+        files = append(files, schemaFile{
+            key:    schemaKey{eventType, version},
+            name:   name,
+            source: string(source),
+        })
+    }
+    return files, nil
+}
+
+// checkSchemaCoverage - 레지스트리의 모든 타입/버전에 스키마 파일이 있는지
+func checkSchemaCoverage(codec string, sources map[schemaKey]string) error {
+    for eventType, latest := range DefaultRegistry.latestVersions() {
+        for version := 1; version <= latest; version++ {
+            if _, ok := sources[schemaKey{eventType, version}]; !ok {
+                return fmt.Errorf("missing %s schema for %s v%d", codec, eventType, version)
+            }
+        }
+    }
+    return nil
+}
+
+// snakeCase - OrderCreated → order_created
+func snakeCase(s string) string {
+    var b strings.Builder
+    for i, r := range s {
+        if unicode.IsUpper(r) {
+            if i > 0 {
+                b.WriteByte('_')
+            }
+            r = unicode.ToLower(r)
+        }
+        b.WriteRune(r)
+    }
+    return b.String()
+}
+
+// codecVersion - 봉투 없는 기존 메시지(버전 0)는 v1
+func codecVersion(version int) int {
+    if version == 0 {
+        return 1
+    }
+    return version
+}
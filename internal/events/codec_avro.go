@@ -0,0 +1,237 @@
+package events
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "time"
+
+    "github.com/hamba/avro/v2"
+)
+
+// avroCodec - schemas/avro의 .avsc 스키마로 직렬화 (스키마 파일마다 별도 캐시로 파싱)
+type avroCodec struct {
+    schemas map[schemaKey]avro.Schema
+    sources map[schemaKey]string
+}
+
+func newAvroCodec() (*avroCodec, error) {
+    files, err := loadSchemaFiles("schemas/avro", ".avsc")
+    if err != nil {
+        return nil, err
+    }
+
+    c := &avroCodec{
+        schemas: make(map[schemaKey]avro.Schema, len(files)),
+        sources: make(map[schemaKey]string, len(files)),
+    }
+    for _, f := range files {
+        schema, err := parseAvroSchema(f.source)
+        if err != nil {
+            return nil, fmt.Errorf("invalid avro schema %s: %w", f.name, err)
+        }
+        c.schemas[f.key] = schema
+        c.sources[f.key] = f.source
+    }
+    if err := checkSchemaCoverage(CodecAvro, c.sources); err != nil {
+        return nil, err
+    }
+    return c, nil
+}
+
+// parseAvroSchema - 버전마다 같은 이름의 레코드가 있으므로 전역 캐시를 쓰지 않음
+func parseAvroSchema(source string) (avro.Schema, error) {
+    return avro.ParseWithCache(source, "", &avro.SchemaCache{})
+}
+
+func (c *avroCodec) Name() string        { return CodecAvro }
+func (c *avroCodec) ContentType() string { return dataContentTypeAvro }
+
+func (c *avroCodec) Schema(eventType string, version int) (*Schema, error) {
+    key := schemaKey{eventType, codecVersion(version)}
+    source, ok := c.sources[key]
+    if !ok {
+        return nil, fmt.Errorf("%w: no avro schema for %s v%d", ErrUnsupportedSchemaVersion, key.eventType, key.version)
+    }
+    return &Schema{Format: SchemaFormatAvro, Definition: source}, nil
+}
+
+func (c *avroCodec) schema(eventType string, version int) (avro.Schema, error) {
+    key := schemaKey{eventType, codecVersion(version)}
+    schema, ok := c.schemas[key]
+    if !ok {
+        return nil, fmt.Errorf("%w: no avro schema for %s v%d", ErrUnsupportedSchemaVersion, key.eventType, key.version)
+    }
+    return schema, nil
+}
+
+// Encode - JSON 값을 스키마 타입에 맞게 변환해 직렬화, 스키마에 없는 필드가 있으면 에러
+func (c *avroCodec) Encode(eventType string, version int, data json.RawMessage) ([]byte, error) {
+    schema, err := c.schema(eventType, version)
+    if err != nil {
+        return nil, err
+    }
+
+    dec := json.NewDecoder(bytes.NewReader(data))
+    dec.UseNumber()
+    var value interface{}
+    if err := dec.Decode(&value); err != nil {
+        return nil, fmt.Errorf("invalid %s v%d payload: %w", eventType, version, err)
+    }
+    native, err := avroNativeValue(schema, value, eventType)
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode %s v%d as avro: %w", eventType, version, err)
+    }
+    return avro.Marshal(schema, native)
+}
+
+func (c *avroCodec) Decode(eventType string, version int, payload []byte) (json.RawMessage, error) {
+    schema, err := c.schema(eventType, version)
+    if err != nil {
+        return nil, err
+    }
+    var value interface{}
+    if err := avro.Unmarshal(schema, payload, &value); err != nil {
+        return nil, fmt.Errorf("invalid avro %s v%d payload: %w", eventType, version, err)
+    }
+    return json.Marshal(value)
+}
+
+// avroNativeValue - JSON 값(UseNumber)을 avro 제네릭 인코딩이 받는 Go 값으로 변환
+// 없는 필드와 null은 타입의 0 값, 시각은 RFC3339 문자열 → time.Time
+func avroNativeValue(schema avro.Schema, value interface{}, path string) (interface{}, error) {
+    switch s := schema.(type) {
+    case *avro.RecordSchema:
+        obj, ok := value.(map[string]interface{})
+        if value != nil && !ok {
+            return nil, fmt.Errorf("%s: expected object", path)
+        }
+        out := make(map[string]interface{}, len(s.Fields()))
+        known := make(map[string]bool, len(s.Fields()))
+        for _, f := range s.Fields() {
+            known[f.Name()] = true
+            v, err := avroNativeValue(f.Type(), obj[f.Name()], path+"."+f.Name())
+            if err != nil {
+                return nil, err
+            }
+            out[f.Name()] = v
+        }
+        for name := range obj {
+            if !known[name] {
+                return nil, fmt.Errorf("%s: unknown field %q", path, name)
+            }
+        }
+        return out, nil
+
+    case *avro.ArraySchema:
+        list, ok := value.([]interface{})
+        if value != nil && !ok {
+            return nil, fmt.Errorf("%s: expected array", path)
+        }
+        out := make([]interface{}, len(list))
+        for i, item := range list {
+            v, err := avroNativeValue(s.Items(), item, fmt.Sprintf("%s[%d]", path, i))
+            if err != nil {
+                return nil, err
+            }
+            out[i] = v
+        }
+        return out, nil
+
+    case *avro.RefSchema:
+        return avroNativeValue(s.Schema(), value, path)
+
+    case *avro.PrimitiveSchema:
+        return avroPrimitiveValue(s, value, path)
+
+    default:
+        return nil, fmt.Errorf("%s: unsupported avro type %s", path, schema.Type())
+    }
+}
+
+func avroPrimitiveValue(s *avro.PrimitiveSchema, value interface{}, path string) (interface{}, error) {
+    if logical := s.Logical(); logical != nil && (logical.Type() == avro.TimestampMicros || logical.Type() == avro.TimestampMillis) {
+        str, _ := value.(string)
+        if str == "" {
+            return time.Time{}, nil
+        }
+        t, err := time.Parse(time.RFC3339Nano, str)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", path, err)
+        }
+        return t, nil
+    }
+
+    switch s.Type() {
+    case avro.String:
+        if value == nil {
+            return "", nil
+        }
+        str, ok := value.(string)
+        if !ok {
+            return nil, fmt.Errorf("%s: expected string", path)
+        }
+        return str, nil
+    case avro.Boolean:
+        if value == nil {
+            return false, nil
+        }
+        b, ok := value.(bool)
+        if !ok {
+            return nil, fmt.Errorf("%s: expected boolean", path)
+        }
+        return b, nil
+    case avro.Int, avro.Long:
+        n, err := jsonNumber(value, path)
+        if err != nil {
+            return nil, err
+        }
+        i, err := n.Int64()
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", path, err)
+        }
+        if s.Type() == avro.Int {
+            return int(i), nil
+        }
+        return i, nil
+    case avro.Float, avro.Double:
+        n, err := jsonNumber(value, path)
+        if err != nil {
+            return nil, err
+        }
+        f, err := n.Float64()
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", path, err)
+        }
+        if s.Type() == avro.Float {
+            return float32(f), nil
+        }
+        return f, nil
+    default:
+        return nil, fmt.Errorf("%s: unsupported avro type %s", path, s.Type())
+    }
+}
+
+func jsonNumber(value interface{}, path string) (json.Number, error) {
+    switch v := value.(type) {
+    case nil:
+        return "0", nil
+    case json.Number:
+        return v, nil
+    default:
+        return "", fmt.Errorf("%s: expected number", path)
+    }
+}
+
+// checkAvroCompatible - 새 스키마(reader)로 기존 스키마(writer)의 데이터를 읽을 수 있는지 (BACKWARD)
+func checkAvroCompatible(newSchema, oldSchema string) error {
+    reader, err := parseAvroSchema(newSchema)
+    if err != nil {
+        return err
+    }
+    writer, err := parseAvroSchema(oldSchema)
+    if err != nil {
+        return err
+    }
+    return avro.NewSchemaCompatibility().Compatible(reader, writer)
+}
@@ -0,0 +1,218 @@
+package events
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "time"
+
+    "github.com/bufbuild/protocompile"
+    "google.golang.org/protobuf/encoding/protojson"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+    "google.golang.org/protobuf/types/dynamicpb"
+)
+
+const protoTimestampName protoreflect.FullName = "google.protobuf.Timestamp"
+
+// protobufCodec - schemas/protobuf의 .proto 파일로 런타임에 메시지를 구성해 직렬화
+// 파일마다 첫 번째 메시지가 이벤트 (Confluent 스키마 레지스트리 기본값과 같음)
+type protobufCodec struct {
+    messages map[schemaKey]protoreflect.MessageDescriptor
+    sources  map[schemaKey]string
+}
+
+func newProtobufCodec() (*protobufCodec, error) {
+    files, err := loadSchemaFiles("schemas/protobuf", ".proto")
+    if err != nil {
+        return nil, err
+    }
+
+    c := &protobufCodec{
+        messages: make(map[schemaKey]protoreflect.MessageDescriptor, len(files)),
+        sources:  make(map[schemaKey]string, len(files)),
+    }
+    for _, f := range files {
+        msg, err := compileProtoMessage(f.name, f.source)
+        if err != nil {
+            return nil, err
+        }
+        c.messages[f.key] = msg
+        c.sources[f.key] = f.source
+    }
+    if err := checkSchemaCoverage(CodecProtobuf, c.sources); err != nil {
+        return nil, err
+    }
+    return c, nil
+}
+
+// compileProtoMessage - .proto 소스 하나를 컴파일해 첫 번째 메시지를 반환 (well-known 타입 import 허용)
+func compileProtoMessage(name, source string) (protoreflect.MessageDescriptor, error) {
+    compiler := protocompile.Compiler{
+        Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+            Accessor: protocompile.SourceAccessorFromMap(map[string]string{name: source}),
+        }),
+    }
+    files, err := compiler.Compile(context.Background(), name)
+    if err != nil {
+        return nil, fmt.Errorf("invalid protobuf schema %s: %w", name, err)
+    }
+    messages := files[0].Messages()
+    if messages.Len() == 0 {
+        return nil, fmt.Errorf("invalid protobuf schema %s: no message", name)
+    }
+    return messages.Get(0), nil
+}
+
+func (c *protobufCodec) Name() string        { return CodecProtobuf }
+func (c *protobufCodec) ContentType() string { return dataContentTypeProtobuf }
+
+func (c *protobufCodec) Schema(eventType string, version int) (*Schema, error) {
+    key := schemaKey{eventType, codecVersion(version)}
+    source, ok := c.sources[key]
+    if !ok {
+        return nil, fmt.Errorf("%w: no protobuf schema for %s v%d", ErrUnsupportedSchemaVersion, key.eventType, key.version)
+    }
+    return &Schema{Format: SchemaFormatProtobuf, Definition: source}, nil
+}
+
+func (c *protobufCodec) descriptor(eventType string, version int) (protoreflect.MessageDescriptor, error) {
+    key := schemaKey{eventType, codecVersion(version)}
+    msg, ok := c.messages[key]
+    if !ok {
+        return nil, fmt.Errorf("%w: no protobuf schema for %s v%d", ErrUnsupportedSchemaVersion, key.eventType, key.version)
+    }
+    return msg, nil
+}
+
+// Encode - JSON 필드 이름(snake_case)으로 메시지를 채움, 스키마에 없는 필드가 있으면 에러
+func (c *protobufCodec) Encode(eventType string, version int, data json.RawMessage) ([]byte, error) {
+    desc, err := c.descriptor(eventType, version)
+    if err != nil {
+        return nil, err
+    }
+    msg := dynamicpb.NewMessage(desc)
+    if err := protojson.Unmarshal(data, msg); err != nil {
+        return nil, fmt.Errorf("failed to encode %s v%d as protobuf: %w", eventType, version, err)
+    }
+    return proto.Marshal(msg)
+}
+
+// Decode - protojson은 int64를 문자열로 내보내므로 스키마를 따라 직접 JSON 값으로 변환
+func (c *protobufCodec) Decode(eventType string, version int, payload []byte) (json.RawMessage, error) {
+    desc, err := c.descriptor(eventType, version)
+    if err != nil {
+        return nil, err
+    }
+    msg := dynamicpb.NewMessage(desc)
+    if err := proto.Unmarshal(payload, msg); err != nil {
+        return nil, fmt.Errorf("invalid protobuf %s v%d payload: %w", eventType, version, err)
+    }
+    return json.Marshal(protoMessageValue(msg))
+}
+
+func protoMessageValue(msg protoreflect.Message) interface{} {
+    desc := msg.Descriptor()
+    if desc.FullName() == protoTimestampName {
+        fields := desc.Fields()
+        seconds := msg.Get(fields.ByName("seconds")).Int()
+        nanos := msg.Get(fields.ByName("nanos")).Int()
+        return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano)
+    }
+
+    out := make(map[string]interface{}, desc.Fields().Len())
+    fields := desc.Fields()
+    for i := 0; i < fields.Len(); i++ {
+        fd := fields.Get(i)
+        if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !msg.Has(fd) {
+            continue
+        }
+        value := msg.Get(fd)
+        if fd.IsList() {
+            list := value.List()
+            items := make([]interface{}, list.Len())
+            for j := range items {
+                items[j] = protoFieldValue(fd, list.Get(j))
+            }
+            out[string(fd.Name())] = items
+            continue
+        }
+        out[string(fd.Name())] = protoFieldValue(fd, value)
+    }
+    return out
+}
+
+func protoFieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+    switch fd.Kind() {
+    case protoreflect.MessageKind, protoreflect.GroupKind:
+        return protoMessageValue(v.Message())
+    case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+        protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+        return v.Int()
+    case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+        return v.Uint()
+    case protoreflect.FloatKind, protoreflect.DoubleKind:
+        return v.Float()
+    case protoreflect.BoolKind:
+        return v.Bool()
+    case protoreflect.EnumKind:
+        if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+            return string(ev.Name())
+        }
+        return int32(v.Enum())
+    case protoreflect.BytesKind:
+        return v.Bytes()
+    default:
+        return v.String()
+    }
+}
+
+// checkProtoCompatible - 새 스키마로 기존 스키마의 데이터를 읽을 수 있는지 (BACKWARD)
+// 와이어는 필드 번호로, JSON 변환은 필드 이름으로 맞추므로 둘 다 바뀌면 안 됨
+// 삭제된 필드는 기본값으로 읽히므로 허용
+func checkProtoCompatible(newSchema, oldSchema string) error {
+    newMsg, err := compileProtoMessage("new.proto", newSchema)
+    if err != nil {
+        return err
+    }
+    oldMsg, err := compileProtoMessage("old.proto", oldSchema)
+    if err != nil {
+        return err
+    }
+    return compareProtoMessages(newMsg, oldMsg, make(map[protoreflect.FullName]bool))
+}
+
+func compareProtoMessages(newMsg, oldMsg protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) error {
+    if seen[oldMsg.FullName()] {
+        return nil
+    }
+    seen[oldMsg.FullName()] = true
+
+    if newMsg.FullName() != oldMsg.FullName() {
+        return fmt.Errorf("message %s renamed to %s", oldMsg.FullName(), newMsg.FullName())
+    }
+
+    oldFields := oldMsg.Fields()
+    for i := 0; i < oldFields.Len(); i++ {
+        of := oldFields.Get(i)
+        if nf := newMsg.Fields().ByName(of.Name()); nf != nil && nf.Number() != of.Number() {
+            return fmt.Errorf("field %s.%s renumbered from %d to %d", oldMsg.FullName(), of.Name(), of.Number(), nf.Number())
+        }
+        nf := newMsg.Fields().ByNumber(of.Number())
+        if nf == nil {
+            continue
+        }
+        if nf.Name() != of.Name() {
+            return fmt.Errorf("field %d of %s renamed from %s to %s", of.Number(), oldMsg.FullName(), of.Name(), nf.Name())
+        }
+        if nf.Kind() != of.Kind() || nf.Cardinality() != of.Cardinality() || nf.IsMap() != of.IsMap() {
+            return fmt.Errorf("field %s.%s changed type", oldMsg.FullName(), of.Name())
+        }
+        if of.Kind() == protoreflect.MessageKind {
+            if err := compareProtoMessages(nf.Message(), of.Message(), seen); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
@@ -0,0 +1,232 @@
+package events
+
+import (
+    "bytes"
+    "context"
+    "encoding/binary"
+    "encoding/json"
+    "errors"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+// sampleEvents - 등록된 이벤트 타입/버전별로 모든 필드를 채운 예시 (timestamp-micros에 맞춰 마이크로초 정밀도)
+func sampleEvents() map[schemaKey]interface{} {
+    at := time.Date(2026, 10, 17, 9, 30, 0, 123456000, time.UTC)
+    price := domain.Money{Amount: 12500, Currency: "KRW"}
+    total := domain.Money{Amount: 37500, Currency: "KRW"}
+    items := []domain.OrderItem{
+        {LineID: 1, ProductID: "PROD-A", ProductName: "Keyboard", Quantity: 2, Price: price, Status: domain.LineStatusActive},
+        {LineID: 2, ProductID: "PROD-B", ProductName: "Mouse", Quantity: 1, Price: price, Status: domain.LineStatusCancelled, CancelledQuantity: 1},
+    }
+    stockResult := StockResultEvent{
+        EventID: "evt-stock", EventType: EventTypeStockDeducted, OrderID: 42,
+        ProductID: "PROD-A", Quantity: 2, Reason: "reserved", Timestamp: at,
+    }
+    failed := stockResult
+    failed.EventType = EventTypeStockDeductionFailed
+    failed.Reason = "out of stock"
+
+    return map[schemaKey]interface{}{
+        {EventTypeOrderCreated, 1}: OrderCreatedEventV1{
+            EventID: "evt-created", OrderID: 42, UserID: "user-1", TotalAmount: 375.5,
+            Items:  []OrderItemV1{{ProductID: "PROD-A", ProductName: "Keyboard", Quantity: 2, Price: 125.25}},
+            Status: "PENDING", Timestamp: at, RequestID: "req-1", IdempotencyKey: "key-1",
+            UserAgent: "curl/8.0", SourceIP: "10.0.0.1",
+        },
+        {EventTypeOrderCreated, 2}: OrderCreatedEvent{
+            EventID: "evt-created", OrderID: 42, UserID: "user-1", TotalAmount: total, Items: items,
+            Status: "PENDING", Timestamp: at, RequestID: "req-1", IdempotencyKey: "key-1",
+            CatalogVersion: "catalog-7", UserAgent: "curl/8.0", SourceIP: "10.0.0.1",
+        },
+        {EventTypeOrderStatusChanged, 1}: OrderStatusChangedEvent{
+            EventID: "evt-status", OrderID: 42, UserID: "user-1",
+            FromStatus: "PENDING", ToStatus: "CONFIRMED", Reason: "stock reserved", Timestamp: at,
+        },
+        {EventTypeOrderLinesCancelled, 1}: OrderLinesCancelledEvent{
+            EventID: "evt-cancel", OrderID: 42, UserID: "user-1",
+            Lines: []domain.LineChange{{
+                LineID: 2, ProductID: "PROD-B", Action: domain.LineActionCancel, Quantity: 1,
+                FromStatus: domain.LineStatusActive, ToStatus: domain.LineStatusCancelled,
+                Reason: "customer request", ChangedAt: at,
+            }},
+            TotalAmount: total, Reason: "customer request", Timestamp: at,
+        },
+        {EventTypeOrderAmended, 1}: OrderAmendedEvent{
+            EventID: "evt-amend", OrderID: 42, UserID: "user-1", Version: 3, Items: items,
+            StockDeltas: []StockDelta{{ProductID: "PROD-A", Quantity: 1}, {ProductID: "PROD-B", Quantity: -1}},
+            TotalAmount: total, CatalogVersion: "catalog-7", Reason: "change quantity", Timestamp: at,
+        },
+        {EventTypeStockDeduction, 1}: StockDeductionEvent{
+            EventID: "evt-deduct", OrderID: 42, ProductID: "PROD-A", Quantity: 2, Timestamp: at,
+        },
+        {EventTypeCompensation, 1}: CompensationEvent{
+            EventID: "evt-compensate", OrderID: 42, ProductID: "PROD-A", Quantity: 2, Reason: "order cancelled", Timestamp: at,
+        },
+        {EventTypeStockDeducted, 1}:        stockResult,
+        {EventTypeStockDeductionFailed, 1}: failed,
+    }
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+    samples := sampleEvents()
+    DefaultRegistry.mu.RLock()
+    for key := range DefaultRegistry.schemas {
+        if _, ok := samples[key]; !ok {
+            t.Errorf("no sample event for %s v%d", key.eventType, key.version)
+        }
+    }
+    DefaultRegistry.mu.RUnlock()
+
+    for _, name := range []string{CodecProtobuf, CodecAvro} {
+        codec, err := NewCodec(name)
+        if err != nil {
+            t.Fatal(err)
+        }
+        for key, event := range samples {
+            data, err := json.Marshal(event)
+            if err != nil {
+                t.Fatal(err)
+            }
+            encoded, err := codec.Encode(key.eventType, key.version, data)
+            if err != nil {
+                t.Errorf("%s Encode(%s v%d): %v", name, key.eventType, key.version, err)
+                continue
+            }
+            decoded, err := codec.Decode(key.eventType, key.version, encoded)
+            if err != nil {
+                t.Errorf("%s Decode(%s v%d): %v", name, key.eventType, key.version, err)
+                continue
+            }
+
+            want := reflect.New(reflect.TypeOf(event))
+            want.Elem().Set(reflect.ValueOf(event))
+            got, err := DefaultRegistry.DecodeVersion(key.eventType, key.version, decoded)
+            if err != nil {
+                t.Errorf("%s %s v%d: %v", name, key.eventType, key.version, err)
+                continue
+            }
+            if !reflect.DeepEqual(got, want.Interface()) {
+                t.Errorf("%s %s v%d round trip\n got %+v\nwant %+v", name, key.eventType, key.version, got, want.Interface())
+            }
+        }
+    }
+}
+
+func TestWireFormat(t *testing.T) {
+    data := []byte{0x0a, 0x03, 'e', 'v', 't'}
+
+    framed := encodeWire(CodecAvro, 258, data)
+    if want := append([]byte{0x00, 0x00, 0x00, 0x01, 0x02}, data...); !bytes.Equal(framed, want) {
+        t.Errorf("avro frame = % x, want % x", framed, want)
+    }
+    framed = encodeWire(CodecProtobuf, 258, data)
+    if want := append([]byte{0x00, 0x00, 0x00, 0x01, 0x02, 0x00}, data...); !bytes.Equal(framed, want) {
+        t.Errorf("protobuf frame = % x, want % x", framed, want)
+    }
+
+    for _, tt := range []struct {
+        name    string
+        codec   string
+        payload []byte
+    }{
+        {"avro", CodecAvro, encodeWire(CodecAvro, 7, data)},
+        {"protobuf short index", CodecProtobuf, encodeWire(CodecProtobuf, 7, data)},
+        // 다른 직렬화기가 [0]을 개수 1 + 인덱스 0으로 쓴 경우
+        {"protobuf full index", CodecProtobuf, append([]byte{0x00, 0x00, 0x00, 0x00, 0x07, 0x02, 0x00}, data...)},
+    } {
+        id, got, err := decodeWire(tt.codec, tt.payload)
+        if err != nil || id != 7 || !bytes.Equal(got, data) {
+            t.Errorf("%s: decodeWire = %d, % x, %v, want 7, % x", tt.name, id, got, err, data)
+        }
+    }
+
+    for _, tt := range []struct {
+        name    string
+        codec   string
+        payload []byte
+    }{
+        {"empty", CodecAvro, nil},
+        {"short header", CodecAvro, []byte{0x00, 0x00, 0x01}},
+        {"no magic byte", CodecAvro, append([]byte{0x01, 0x00, 0x00, 0x00, 0x07}, data...)},
+        {"unframed", CodecProtobuf, data},
+        {"missing message index", CodecProtobuf, []byte{0x00, 0x00, 0x00, 0x00, 0x07}},
+        {"nested message", CodecProtobuf, append([]byte{0x00, 0x00, 0x00, 0x00, 0x07, 0x04, 0x00, 0x02}, data...)},
+        {"second message", CodecProtobuf, append([]byte{0x00, 0x00, 0x00, 0x00, 0x07, 0x02, 0x02}, data...)},
+    } {
+        if _, _, err := decodeWire(tt.codec, tt.payload); !errors.Is(err, ErrWireFormat) {
+            t.Errorf("%s: err = %v, want ErrWireFormat", tt.name, err)
+        }
+    }
+}
+
+func TestEncoderFramesRegisteredSchemaID(t *testing.T) {
+    event := sampleEvents()[schemaKey{EventTypeOrderCreated, 2}]
+
+    for _, name := range []string{CodecProtobuf, CodecAvro} {
+        // 코덱마다 별도 레지스트리 (subject의 스키마 형식은 바꿀 수 없음)
+        registry, err := NewFileSchemaRegistry(t.TempDir())
+        if err != nil {
+            t.Fatal(err)
+        }
+        for _, mode := range []ContentMode{ContentModeStructured, ContentModeBinary} {
+            codec, err := NewCodec(name)
+            if err != nil {
+                t.Fatal(err)
+            }
+            enc, err := newMessageEncoder(TopicOrderEvents, Serialization{Mode: mode, Codec: codec, Registry: registry})
+            if err != nil {
+                t.Fatal(err)
+            }
+            msg := encodeEvent(t, enc, EventTypeOrderCreated, 42, event).msg
+
+            schema, err := registry.Register(context.Background(), "order-events-"+cloudEventTypePrefix+"OrderCreated.v2", *mustSchema(t, codec))
+            if err != nil {
+                t.Fatal(err)
+            }
+            payload := msg.Value
+            if mode == ContentModeStructured {
+                var ce CloudEvent
+                if err := json.Unmarshal(msg.Value, &ce); err != nil {
+                    t.Fatal(err)
+                }
+                if ce.DataSchema != schema.URI {
+                    t.Errorf("%s/%s dataschema = %q, want %q", name, mode, ce.DataSchema, schema.URI)
+                }
+                payload = ce.DataBase64
+            }
+            if len(payload) < wireHeaderSize || payload[0] != wireMagicByte {
+                t.Fatalf("%s/%s payload % x does not start with the magic byte", name, mode, payload)
+            }
+            if id := int(binary.BigEndian.Uint32(payload[1:wireHeaderSize])); id != schema.ID {
+                t.Errorf("%s/%s schema ID = %d, want %d", name, mode, id, schema.ID)
+            }
+
+            ce, err := FromKafkaMessage(msg)
+            if err != nil {
+                t.Fatalf("%s/%s FromKafkaMessage: %v", name, mode, err)
+            }
+            got, err := DefaultRegistry.Decode(ce)
+            if err != nil {
+                t.Fatal(err)
+            }
+            want := event.(OrderCreatedEvent)
+            if !reflect.DeepEqual(got, &want) {
+                t.Errorf("%s/%s decoded %+v, want %+v", name, mode, got, want)
+            }
+        }
+    }
+}
+
+// mustSchema - 인코더가 등록한 것과 같은 스키마 (다시 등록하면 같은 ID를 돌려받음)
+func mustSchema(t *testing.T, codec Codec) *Schema {
+    t.Helper()
+    schema, err := codec.Schema(EventTypeOrderCreated, 2)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return schema
+}
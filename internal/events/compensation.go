@@ -3,6 +3,8 @@ package events
 import (
     "context"
     "encoding/json"
+    "fmt"
+    "strconv"
     "time"
     
     "github.com/segmentio/kafka-go"
@@ -10,43 +12,49 @@ import (
 )
 
 type CompensationProducer struct {
-    writer *kafka.Writer
-    logger *zap.Logger
+    writer  *kafka.Writer
+    encoder *messageEncoder
+    health  *KafkaHealthChecker
+    logger  *zap.Logger
 }
 
-func NewCompensationProducer(brokers string, logger *zap.Logger) (*CompensationProducer, error) {
+func NewCompensationProducer(conn KafkaConnection, ser Serialization, health *KafkaHealthChecker, logger *zap.Logger) (*CompensationProducer, error) {
+    encoder, err := newMessageEncoder(TopicCompensationEvents, ser)
+    if err != nil {
+        return nil, err
+    }
+
     writer := &kafka.Writer{
-        Addr:     kafka.TCP(brokers),
-        Topic:    "compensation-events",
-        Balancer: &kafka.LeastBytes{},
+        Addr:      kafka.TCP(conn.Brokers...),
+        Transport: conn.transport(),
+        Topic:     TopicCompensationEvents,
+        Balancer:  &kafka.Murmur2Balancer{}, // 같은 키는 같은 파티션 (Java 클라이언트 기본 파티셔너와 같은 해시)
     }
     
     return &CompensationProducer{
-        writer: writer,
-        logger: logger,
+        writer:  writer,
+        encoder: encoder,
+        health:  health,
+        logger:  logger,
     }, nil
 }
 
 func (p *CompensationProducer) PublishCompensation(event CompensationEvent) error {
-    eventBytes, err := json.Marshal(event)
+    ce, err := NewCloudEvent(EventTypeCompensation, event.EventID, event)
     if err != nil {
         p.logger.Error("Failed to marshal compensation event", zap.Error(err))
         return err
     }
-    
-    msg := kafka.Message{
-        Key:   []byte(event.EventID),
-        Value: eventBytes,
+    eventBytes, err := json.Marshal(ce)
+    if err != nil {
+        p.logger.Error("Failed to marshal compensation event", zap.Error(err))
+        return err
     }
     
     ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
     defer cancel()
     
-    err = p.writer.WriteMessages(ctx, msg)
-    if err != nil {
-        p.logger.Error("Failed to publish compensation event", 
-            zap.String("event_id", event.EventID),
-            zap.Error(err))
+    if err := p.PublishMessage(ctx, strconv.Itoa(event.OrderID), eventBytes); err != nil {
         return err
     }
     
@@ -57,6 +65,36 @@ func (p *CompensationProducer) PublishCompensation(event CompensationEvent) erro
     return nil
 }
 
+// PublishMessage - 이미 직렬화된 보상 CloudEvent를 설정된 코덱과 모드로 발행 (아웃박스 릴레이용)
+func (p *CompensationProducer) PublishMessage(ctx context.Context, key string, payload []byte) error {
+    msg, err := p.encoder.encode(ctx, key, payload)
+    if err != nil {
+        return err
+    }
+    
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+    
+    if err := p.writer.WriteMessages(ctx, msg); err != nil {
+        p.logger.Error("Failed to publish compensation event", 
+            zap.String("key", key),
+            zap.Error(err))
+        return err
+    }
+    
+    return nil
+}
+
+func (p *CompensationProducer) HealthCheck() error {
+    if p.writer == nil {
+        return fmt.Errorf("kafka writer not initialized")
+    }
+    if p.health != nil {
+        return p.health.HealthCheck()
+    }
+    return nil
+}
+
 func (p *CompensationProducer) Close() error {
     if p.writer != nil {
         return p.writer.Close()
@@ -0,0 +1,135 @@
+package events
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "fmt"
+    "net"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+    "github.com/segmentio/kafka-go/sasl"
+    "github.com/segmentio/kafka-go/sasl/plain"
+    "github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// SASL 메커니즘 (KAFKA_SASL_MECHANISM)
+const (
+    SASLPlain       = "plain"
+    SASLScramSHA256 = "scram-sha-256"
+    SASLScramSHA512 = "scram-sha-512"
+)
+
+const defaultKafkaPort = "9092"
+
+// KafkaConnection - 브로커 목록과 TLS/SASL 설정 (프로듀서와 컨슈머가 공유)
+type KafkaConnection struct {
+    Brokers []string
+    TLS     *tls.Config
+    SASL    sasl.Mechanism
+}
+
+// ParseBrokers - "host:port,host:port" 형식의 브로커 목록 (공백/빈 항목 무시, 포트가 없으면 9092)
+func ParseBrokers(s string) ([]string, error) {
+    var brokers []string
+    for _, broker := range strings.Split(s, ",") {
+        broker = strings.TrimSpace(broker)
+        if broker == "" {
+            continue
+        }
+        host, port, err := net.SplitHostPort(broker)
+        if err != nil {
+            // 포트 없는 호스트 (IPv6는 [::1] 형식)
+            host, port = strings.Trim(broker, "[]"), defaultKafkaPort
+        }
+        if host == "" || port == "" {
+            return nil, fmt.Errorf("invalid kafka broker %q", broker)
+        }
+        brokers = append(brokers, net.JoinHostPort(host, port))
+    }
+    if len(brokers) == 0 {
+        return nil, fmt.Errorf("no kafka brokers configured")
+    }
+    return brokers, nil
+}
+
+// NewSASLMechanism - plain | scram-sha-256 | scram-sha-512 (빈 문자열이면 SASL 없음)
+func NewSASLMechanism(mechanism, username, password string) (sasl.Mechanism, error) {
+    switch strings.ToLower(mechanism) {
+    case "":
+        return nil, nil
+    case SASLPlain:
+        return plain.Mechanism{Username: username, Password: password}, nil
+    case SASLScramSHA256:
+        return scram.Mechanism(scram.SHA256, username, password)
+    case SASLScramSHA512:
+        return scram.Mechanism(scram.SHA512, username, password)
+    default:
+        return nil, fmt.Errorf("unknown kafka sasl mechanism %q", mechanism)
+    }
+}
+
+// KafkaTLSOptions - 브로커 TLS 설정
+type KafkaTLSOptions struct {
+    // CAFile - 브로커 인증서를 검증할 CA (비어 있으면 시스템 루트)
+    CAFile string
+    // CertFile/KeyFile - 클라이언트 인증서 (mTLS)
+    CertFile string
+    KeyFile  string
+    // ServerName - 인증서 검증에 쓸 호스트 이름 (비어 있으면 브로커 주소)
+    ServerName         string
+    InsecureSkipVerify bool
+}
+
+// NewKafkaTLSConfig - 파일 기반 브로커 TLS 설정 (SPIFFE SVID 클라이언트 인증서는 호출자가 GetClientCertificate로 지정)
+func NewKafkaTLSConfig(opts KafkaTLSOptions) (*tls.Config, error) {
+    cfg := &tls.Config{
+        MinVersion:         tls.VersionTLS12,
+        ServerName:         opts.ServerName,
+        InsecureSkipVerify: opts.InsecureSkipVerify,
+    }
+
+    if opts.CAFile != "" {
+        pem, err := os.ReadFile(opts.CAFile)
+        if err != nil {
+            return nil, fmt.Errorf("failed to read kafka CA file: %w", err)
+        }
+        pool := x509.NewCertPool()
+        if !pool.AppendCertsFromPEM(pem) {
+            return nil, fmt.Errorf("no certificates found in kafka CA file %s", opts.CAFile)
+        }
+        cfg.RootCAs = pool
+    }
+
+    if opts.CertFile != "" || opts.KeyFile != "" {
+        cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+        if err != nil {
+            return nil, fmt.Errorf("failed to load kafka client certificate: %w", err)
+        }
+        cfg.Certificates = []tls.Certificate{cert}
+    }
+    return cfg, nil
+}
+
+// transport - 프로듀서용 (TLS/SASL이 없으면 nil → kafka-go 기본 Transport)
+func (c KafkaConnection) transport() kafka.RoundTripper {
+    if c.TLS == nil && c.SASL == nil {
+        return nil
+    }
+    return &kafka.Transport{
+        TLS:  c.TLS,
+        SASL: c.SASL,
+    }
+}
+
+// dialer - 컨슈머용
+func (c KafkaConnection) dialer() *kafka.Dialer {
+    return &kafka.Dialer{
+        Timeout:       10 * time.Second,
+        DualStack:     true,
+        TLS:           c.TLS,
+        SASLMechanism: c.SASL,
+    }
+}
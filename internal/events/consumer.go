@@ -0,0 +1,235 @@
+package events
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "sync"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+    "go.uber.org/zap"
+)
+
+// ErrPermanent - 재시도해도 처리할 수 없는 메시지 (로그 후 커밋하고 건너뜀)
+var ErrPermanent = errors.New("permanent message failure")
+
+// MessageHandler - 처리에 성공해야 오프셋이 커밋됨 (at-least-once)
+type MessageHandler func(ctx context.Context, msg kafka.Message) error
+
+// messageReader - KafkaConsumer가 쓰는 kafka.Reader 메서드
+type messageReader interface {
+    Config() kafka.ReaderConfig
+    FetchMessage(ctx context.Context) (kafka.Message, error)
+    CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+    Close() error
+}
+
+type KafkaConsumer struct {
+    reader     messageReader
+    handler    MessageHandler
+    logger     *zap.Logger
+    minBackoff time.Duration
+    maxBackoff time.Duration
+
+    mu       sync.Mutex
+    fetchErr error // 마지막 FetchMessage 실패 (성공하면 nil)
+    stopped  bool
+}
+
+func NewKafkaConsumer(conn KafkaConnection, groupID string, topics []string, handler MessageHandler, logger *zap.Logger) (*KafkaConsumer, error) {
+    if groupID == "" {
+        return nil, fmt.Errorf("kafka consumer group id is required")
+    }
+
+    reader := kafka.NewReader(kafka.ReaderConfig{
+        Brokers:     conn.Brokers,
+        Dialer:      conn.dialer(),
+        GroupID:     groupID,
+        GroupTopics: topics,
+        MinBytes:    1,
+        MaxBytes:    10e6,
+        // 오프셋은 처리 완료 후 직접 커밋
+        CommitInterval: 0,
+    })
+
+    return &KafkaConsumer{
+        reader:     reader,
+        handler:    handler,
+        logger:     logger,
+        minBackoff: 500 * time.Millisecond,
+        maxBackoff: 30 * time.Second,
+    }, nil
+}
+
+// Run - ctx가 취소될 때까지 메시지를 읽어 처리
+// 브로커 장애 등으로 메시지를 가져오지 못하면 백오프하며 다시 시도 (그동안 HealthCheck는 에러)
+func (c *KafkaConsumer) Run(ctx context.Context) error {
+    cfg := c.reader.Config()
+    c.logger.Info("Kafka consumer started",
+        zap.String("group_id", cfg.GroupID),
+        zap.Strings("topics", cfg.GroupTopics))
+    defer c.stop()
+
+    backoff := c.minBackoff
+    for {
+        msg, err := c.reader.FetchMessage(ctx)
+        if err != nil {
+            if ctx.Err() != nil {
+                c.logger.Info("Kafka consumer stopped")
+                return nil
+            }
+            // 닫힌 reader는 다시 읽을 수 없음
+            if errors.Is(err, io.EOF) {
+                return fmt.Errorf("failed to fetch message: %w", err)
+            }
+            c.setFetchErr(err)
+            c.logger.Warn("Failed to fetch message, retrying",
+                zap.Duration("backoff", backoff),
+                zap.Error(err))
+
+            select {
+            case <-ctx.Done():
+                c.logger.Info("Kafka consumer stopped")
+                return nil
+            case <-time.After(backoff):
+            }
+            if backoff *= 2; backoff > c.maxBackoff {
+                backoff = c.maxBackoff
+            }
+            continue
+        }
+        c.setFetchErr(nil)
+        backoff = c.minBackoff
+
+        if err := c.handle(ctx, msg); err != nil {
+            // ctx 취소로 처리를 끝내지 못한 메시지는 커밋하지 않음 → 재시작 후 다시 처리
+            c.logger.Info("Kafka consumer stopped")
+            return nil
+        }
+
+        if err := c.reader.CommitMessages(ctx, msg); err != nil {
+            if ctx.Err() != nil {
+                c.logger.Info("Kafka consumer stopped")
+                return nil
+            }
+            c.logger.Error("Failed to commit offset",
+                zap.String("topic", msg.Topic),
+                zap.Int("partition", msg.Partition),
+                zap.Int64("offset", msg.Offset),
+                zap.Error(err))
+        }
+    }
+}
+
+// handle - 성공하거나 영구 실패할 때까지 백오프하며 재시도, ctx 취소 시에만 에러 반환
+func (c *KafkaConsumer) handle(ctx context.Context, msg kafka.Message) error {
+    backoff := c.minBackoff
+    for attempt := 1; ; attempt++ {
+        err := c.handler(ctx, msg)
+        if err == nil {
+            return nil
+        }
+        if errors.Is(err, ErrPermanent) {
+            c.logger.Error("Skipping unprocessable message",
+                zap.String("topic", msg.Topic),
+                zap.Int("partition", msg.Partition),
+                zap.Int64("offset", msg.Offset),
+                zap.Error(err))
+            return nil
+        }
+
+        c.logger.Warn("Message handling failed, retrying",
+            zap.String("topic", msg.Topic),
+            zap.Int("partition", msg.Partition),
+            zap.Int64("offset", msg.Offset),
+            zap.Int("attempt", attempt),
+            zap.Duration("backoff", backoff),
+            zap.Error(err))
+
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-time.After(backoff):
+        }
+        if backoff *= 2; backoff > c.maxBackoff {
+            backoff = c.maxBackoff
+        }
+    }
+}
+
+func (c *KafkaConsumer) setFetchErr(err error) {
+    c.mu.Lock()
+    c.fetchErr = err
+    c.mu.Unlock()
+}
+
+func (c *KafkaConsumer) stop() {
+    c.mu.Lock()
+    c.stopped = true
+    c.mu.Unlock()
+}
+
+// HealthCheck - Run이 끝났거나 마지막으로 메시지를 가져오는 데 실패했으면 에러
+func (c *KafkaConsumer) HealthCheck() error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if c.stopped {
+        return errors.New("kafka consumer stopped")
+    }
+    if c.fetchErr != nil {
+        return fmt.Errorf("kafka consumer failed to fetch: %w", c.fetchErr)
+    }
+    return nil
+}
+
+func (c *KafkaConsumer) Close() error {
+    if c.reader != nil {
+        return c.reader.Close()
+    }
+    return nil
+}
+
+// StockResultHandler - stock-events 메시지를 StockResultEvent로 디코딩해 전달
+// CloudEvents(binary/structured)는 레지스트리로 최신 스키마까지 업캐스트하고, 봉투 없는 기존 JSON도 그대로 받음
+func StockResultHandler(handle func(ctx context.Context, event StockResultEvent) error) MessageHandler {
+    return func(ctx context.Context, msg kafka.Message) error {
+        event, err := decodeStockResult(msg)
+        if err != nil {
+            return fmt.Errorf("%w: invalid stock result payload: %v", ErrPermanent, err)
+        }
+        return handle(ctx, event)
+    }
+}
+
+func decodeStockResult(msg kafka.Message) (StockResultEvent, error) {
+    var event StockResultEvent
+    ce, err := FromKafkaMessage(msg)
+    if err != nil {
+        return event, err
+    }
+    if ce == nil {
+        err := json.Unmarshal(msg.Value, &event)
+        return event, err
+    }
+
+    decoded, err := DefaultRegistry.Decode(ce)
+    if err != nil {
+        return event, err
+    }
+    result, ok := decoded.(*StockResultEvent)
+    if !ok {
+        return event, fmt.Errorf("%w: %s is not a stock result", ErrUnknownEventType, ce.Type)
+    }
+    event = *result
+    // 봉투 속성만 채워 보내는 발행자도 허용
+    if event.EventType == "" {
+        event.EventType = ce.EventType()
+    }
+    if event.EventID == "" {
+        event.EventID = ce.ID
+    }
+    return event, nil
+}
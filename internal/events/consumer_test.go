@@ -0,0 +1,127 @@
+package events
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+    "go.uber.org/zap"
+)
+
+// fakeReader - fetchErrs를 차례로 반환한 뒤 msgs를 하나씩 내주고, 다 내주면 ctx 취소까지 대기
+type fakeReader struct {
+    mu        sync.Mutex
+    fetchErrs []error
+    msgs      []kafka.Message
+    committed []int64
+}
+
+func (r *fakeReader) Config() kafka.ReaderConfig { return kafka.ReaderConfig{GroupID: "test"} }
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+    r.mu.Lock()
+    if len(r.fetchErrs) > 0 {
+        err := r.fetchErrs[0]
+        r.fetchErrs = r.fetchErrs[1:]
+        r.mu.Unlock()
+        return kafka.Message{}, err
+    }
+    if len(r.msgs) > 0 {
+        msg := r.msgs[0]
+        r.msgs = r.msgs[1:]
+        r.mu.Unlock()
+        return msg, nil
+    }
+    r.mu.Unlock()
+    <-ctx.Done()
+    return kafka.Message{}, ctx.Err()
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    for _, msg := range msgs {
+        r.committed = append(r.committed, msg.Offset)
+    }
+    return nil
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+func newTestConsumer(reader messageReader, handler MessageHandler) *KafkaConsumer {
+    return &KafkaConsumer{
+        reader:     reader,
+        handler:    handler,
+        logger:     zap.NewNop(),
+        minBackoff: time.Millisecond,
+        maxBackoff: 5 * time.Millisecond,
+    }
+}
+
+func TestConsumerRetriesFetchErrors(t *testing.T) {
+    brokerDown := errors.New("broker unavailable")
+    reader := &fakeReader{
+        fetchErrs: []error{brokerDown, brokerDown, brokerDown},
+        msgs:      []kafka.Message{{Offset: 1}, {Offset: 2}},
+    }
+    handled := make(chan int64, 2)
+    consumer := newTestConsumer(reader, func(ctx context.Context, msg kafka.Message) error {
+        handled <- msg.Offset
+        return nil
+    })
+
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan error, 1)
+    go func() { done <- consumer.Run(ctx) }()
+
+    for _, want := range []int64{1, 2} {
+        select {
+        case got := <-handled:
+            if got != want {
+                t.Fatalf("handled offset %d, want %d", got, want)
+            }
+        case <-time.After(time.Second):
+            t.Fatal("consumer stopped handling after fetch errors")
+        }
+    }
+    if err := consumer.HealthCheck(); err != nil {
+        t.Errorf("health after recovery = %v, want nil", err)
+    }
+
+    cancel()
+    if err := <-done; err != nil {
+        t.Errorf("Run = %v, want nil on cancel", err)
+    }
+    if err := consumer.HealthCheck(); err == nil {
+        t.Error("health after stop = nil, want error")
+    }
+}
+
+func TestConsumerHealthReportsFetchFailure(t *testing.T) {
+    brokerDown := errors.New("broker unavailable")
+    errs := make([]error, 1000)
+    for i := range errs {
+        errs[i] = brokerDown
+    }
+    consumer := newTestConsumer(&fakeReader{fetchErrs: errs}, func(ctx context.Context, msg kafka.Message) error {
+        return nil
+    })
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go consumer.Run(ctx)
+
+    deadline := time.Now().Add(time.Second)
+    for consumer.HealthCheck() == nil {
+        if time.Now().After(deadline) {
+            t.Fatal("health stayed nil while fetches failed")
+        }
+        time.Sleep(time.Millisecond)
+    }
+    if err := consumer.HealthCheck(); !errors.Is(err, brokerDown) {
+        t.Errorf("health = %v, want fetch error", err)
+    }
+}
@@ -5,20 +5,83 @@ import (
     "github.com/cloud-wave-best-zizon/order-service/internal/domain"
 )
 
+const (
+    TopicOrderEvents        = "order-events"
+    TopicCompensationEvents = "compensation-events"
+    TopicStockEvents        = "stock-events"
+)
+
+const (
+    EventTypeOrderCreated       = "OrderCreated"
+    EventTypeOrderStatusChanged = "OrderStatusChanged"
+    EventTypeCompensation       = "Compensation"
+    EventTypeOrderLinesCancelled = "OrderLinesCancelled"
+    EventTypeOrderAmended        = "OrderAmended"
+    EventTypeStockDeduction      = "StockDeduction"
+
+    // Product Service가 발행하는 재고 처리 결과
+    EventTypeStockDeducted        = "StockDeducted"
+    EventTypeStockDeductionFailed = "StockDeductionFailed"
+)
+
 type OrderCreatedEvent struct {
     EventID     string             `json:"event_id"`
     OrderID     int                `json:"order_id"`
     UserID      string             `json:"user_id"`
-    TotalAmount float64            `json:"total_amount"`
+    TotalAmount domain.Money       `json:"total_amount"`
     Items       []domain.OrderItem `json:"items"`
     Status      string             `json:"status"`
     Timestamp   time.Time          `json:"timestamp"`
     RequestID   string             `json:"request_id"`
     IdempotencyKey string             `json:"idempotency_key"`
+    CatalogVersion string             `json:"catalog_version,omitempty"`
     UserAgent      string             `json:"user_agent"`
     SourceIP       string             `json:"source_ip"`
 }
 
+type OrderStatusChangedEvent struct {
+    EventID    string    `json:"event_id"`
+    OrderID    int       `json:"order_id"`
+    UserID     string    `json:"user_id"`
+    FromStatus string    `json:"from_status"`
+    ToStatus   string    `json:"to_status"`
+    Reason     string    `json:"reason"`
+    Timestamp  time.Time `json:"timestamp"`
+}
+
+// OrderLinesCancelledEvent - 주문 항목 부분 취소
+type OrderLinesCancelledEvent struct {
+    EventID     string              `json:"event_id"`
+    OrderID     int                 `json:"order_id"`
+    UserID      string              `json:"user_id"`
+    Lines       []domain.LineChange `json:"lines"`
+    TotalAmount domain.Money        `json:"total_amount"`
+    Reason      string              `json:"reason"`
+    Timestamp   time.Time           `json:"timestamp"`
+}
+
+// OrderAmendedEvent - PENDING 주문의 항목 변경, 재고는 StockDeltas만큼만 조정
+type OrderAmendedEvent struct {
+    EventID     string             `json:"event_id"`
+    OrderID     int                `json:"order_id"`
+    UserID      string             `json:"user_id"`
+    Version     int                `json:"version"`
+    Items       []domain.OrderItem `json:"items"`
+    StockDeltas []StockDelta       `json:"stock_deltas"`
+    TotalAmount domain.Money       `json:"total_amount"`
+    // 추가된 항목의 단가를 산정한 가격 카탈로그 버전 (추가 항목이 없으면 주문 생성 시 버전)
+    CatalogVersion string             `json:"catalog_version,omitempty"`
+    Reason      string             `json:"reason,omitempty"`
+    Timestamp   time.Time          `json:"timestamp"`
+}
+
+// StockDelta - 양수면 추가 차감, 음수면 복구할 수량
+type StockDelta struct {
+    ProductID string `json:"product_id"`
+    Quantity  int    `json:"quantity"`
+}
+
+// StockDeductionEvent - 상품 하나의 재고 차감 요청
 type StockDeductionEvent struct {
     EventID   string    `json:"event_id"`
     OrderID   int       `json:"order_id"`
@@ -27,9 +90,23 @@ type StockDeductionEvent struct {
     Timestamp time.Time `json:"timestamp"`
 }
 
+// StockResultEvent - Product Service의 재고 차감 결과
+type StockResultEvent struct {
+    EventID   string    `json:"event_id"`
+    EventType string    `json:"event_type"`
+    OrderID   int       `json:"order_id"`
+    ProductID string    `json:"product_id,omitempty"`
+    Quantity  int       `json:"quantity,omitempty"`
+    Reason    string    `json:"reason,omitempty"`
+    Timestamp time.Time `json:"timestamp"`
+}
+
+// CompensationEvent - ProductID가 있으면 해당 상품 수량만큼 재고 복구
 type CompensationEvent struct {
     EventID   string    `json:"event_id"`
     OrderID   int       `json:"order_id"`
+    ProductID string    `json:"product_id,omitempty"`
+    Quantity  int       `json:"quantity,omitempty"`
     Reason    string    `json:"reason"`
     Timestamp time.Time `json:"timestamp"`
 }
\ No newline at end of file
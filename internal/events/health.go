@@ -0,0 +1,228 @@
+package events
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// Kafka 상태
+const (
+    KafkaHealthy   = "healthy"
+    KafkaDegraded  = "degraded" // 일부 브로커에 연결할 수 없지만 발행은 가능
+    KafkaUnhealthy = "unhealthy"
+)
+
+// BrokerHealth - 설정된 브로커 하나에 직접 연결해 메타데이터를 받은 결과
+type BrokerHealth struct {
+    Address   string `json:"address"`
+    Status    string `json:"status"`
+    LatencyMs int64  `json:"latency_ms"`
+    Error     string `json:"error,omitempty"`
+}
+
+// TopicHealth - 토픽 존재 여부와 리더 없는 파티션 수
+type TopicHealth struct {
+    Name              string `json:"name"`
+    Status            string `json:"status"`
+    Partitions        int    `json:"partitions"`
+    OfflinePartitions int    `json:"offline_partitions"`
+    Error             string `json:"error,omitempty"`
+}
+
+// KafkaHealth - /api/v1/health에 그대로 내보내는 점검 결과
+type KafkaHealth struct {
+    Status    string         `json:"status"`
+    ClusterID string         `json:"cluster_id,omitempty"`
+    CheckedAt time.Time      `json:"checked_at"`
+    Brokers   []BrokerHealth `json:"brokers"`
+    Topics    []TopicHealth  `json:"topics"`
+}
+
+// Err - unhealthy면 원인 요약
+func (h *KafkaHealth) Err() error {
+    if h.Status != KafkaUnhealthy {
+        return nil
+    }
+    for _, t := range h.Topics {
+        if t.Status == KafkaUnhealthy {
+            return fmt.Errorf("kafka topic %s: %s", t.Name, t.Error)
+        }
+    }
+    return errors.New("no kafka broker reachable")
+}
+
+// KafkaHealthChecker - 브로커마다 직접 연결해 토픽 메타데이터를 조회 (결과는 CacheTTL 동안 재사용)
+// 헬스 체크가 잦아도 브로커 부하가 늘지 않도록 점검은 한 번에 하나만 실행
+type KafkaHealthChecker struct {
+    conn     KafkaConnection
+    topics   []string
+    cacheTTL time.Duration
+    timeout  time.Duration
+
+    mu   sync.Mutex
+    last *KafkaHealth
+}
+
+func NewKafkaHealthChecker(conn KafkaConnection, topics []string, cacheTTL, timeout time.Duration) *KafkaHealthChecker {
+    if cacheTTL <= 0 {
+        cacheTTL = 10 * time.Second
+    }
+    if timeout <= 0 {
+        timeout = 3 * time.Second
+    }
+    return &KafkaHealthChecker{
+        conn:     conn,
+        topics:   topics,
+        cacheTTL: cacheTTL,
+        timeout:  timeout,
+    }
+}
+
+// Check - 캐시가 유효하면 마지막 결과, 아니면 새로 점검
+func (c *KafkaHealthChecker) Check(ctx context.Context) *KafkaHealth {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if c.last != nil && time.Since(c.last.CheckedAt) < c.cacheTTL {
+        return c.last
+    }
+    // 결과를 다른 요청과 공유하므로 요청 취소와 무관하게 끝까지 점검
+    ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+    defer cancel()
+    c.last = c.check(ctx)
+    return c.last
+}
+
+// HealthCheck - EventPublisher.HealthCheck 용
+func (c *KafkaHealthChecker) HealthCheck() error {
+    return c.Check(context.Background()).Err()
+}
+
+func (c *KafkaHealthChecker) check(ctx context.Context) *KafkaHealth {
+    health := &KafkaHealth{
+        CheckedAt: time.Now().UTC(),
+        Brokers:   make([]BrokerHealth, len(c.conn.Brokers)),
+    }
+
+    responses := make([]*kafka.MetadataResponse, len(c.conn.Brokers))
+    var wg sync.WaitGroup
+    for i, addr := range c.conn.Brokers {
+        wg.Add(1)
+        go func(i int, addr string) {
+            defer wg.Done()
+            health.Brokers[i], responses[i] = c.probe(ctx, addr)
+        }(i, addr)
+    }
+    wg.Wait()
+
+    var meta *kafka.MetadataResponse
+    reachable := 0
+    for _, res := range responses {
+        if res != nil {
+            reachable++
+            if meta == nil {
+                meta = res
+            }
+        }
+    }
+
+    health.Topics = c.topicHealth(meta)
+    switch {
+    case meta == nil:
+        health.Status = KafkaUnhealthy
+    default:
+        health.ClusterID = meta.ClusterID
+        health.Status = KafkaHealthy
+        if reachable < len(c.conn.Brokers) {
+            health.Status = KafkaDegraded
+        }
+        for _, t := range health.Topics {
+            if t.Status == KafkaUnhealthy {
+                health.Status = KafkaUnhealthy
+            } else if t.Status == KafkaDegraded && health.Status == KafkaHealthy {
+                health.Status = KafkaDegraded
+            }
+        }
+    }
+    return health
+}
+
+// probe - 새 Transport로 연결해 캐시가 아닌 브로커의 실제 메타데이터 응답을 받음
+func (c *KafkaHealthChecker) probe(ctx context.Context, addr string) (BrokerHealth, *kafka.MetadataResponse) {
+    transport := &kafka.Transport{
+        DialTimeout: c.timeout,
+        TLS:         c.conn.TLS,
+        SASL:        c.conn.SASL,
+    }
+    defer transport.CloseIdleConnections()
+
+    client := &kafka.Client{
+        Addr:      kafka.TCP(addr),
+        Timeout:   c.timeout,
+        Transport: transport,
+    }
+
+    start := time.Now()
+    res, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: c.topics})
+    broker := BrokerHealth{
+        Address:   addr,
+        LatencyMs: time.Since(start).Milliseconds(),
+    }
+    if err != nil {
+        broker.Status = KafkaUnhealthy
+        broker.Error = err.Error()
+        return broker, nil
+    }
+    broker.Status = KafkaHealthy
+    return broker, res
+}
+
+// topicHealth - 토픽이 없거나 모든 파티션에 리더가 없으면 unhealthy, 일부만 없으면 degraded
+func (c *KafkaHealthChecker) topicHealth(meta *kafka.MetadataResponse) []TopicHealth {
+    topics := make([]TopicHealth, len(c.topics))
+    for i, name := range c.topics {
+        topics[i] = TopicHealth{Name: name, Status: KafkaUnhealthy, Error: "no broker reachable"}
+    }
+    if meta == nil {
+        return topics
+    }
+
+    byName := make(map[string]kafka.Topic, len(meta.Topics))
+    for _, t := range meta.Topics {
+        byName[t.Name] = t
+    }
+    for i := range topics {
+        t, ok := byName[topics[i].Name]
+        switch {
+        case !ok:
+            topics[i].Error = "topic not found"
+            continue
+        case t.Error != nil:
+            topics[i].Error = t.Error.Error()
+            continue
+        }
+
+        topics[i].Error = ""
+        topics[i].Partitions = len(t.Partitions)
+        for _, p := range t.Partitions {
+            if p.Error != nil || p.Leader.Host == "" {
+                topics[i].OfflinePartitions++
+            }
+        }
+        switch {
+        case topics[i].Partitions == 0 || topics[i].OfflinePartitions == topics[i].Partitions:
+            topics[i].Status = KafkaUnhealthy
+            topics[i].Error = "no partition leader available"
+        case topics[i].OfflinePartitions > 0:
+            topics[i].Status = KafkaDegraded
+        default:
+            topics[i].Status = KafkaHealthy
+        }
+    }
+    return topics
+}
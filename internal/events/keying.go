@@ -0,0 +1,101 @@
+package events
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+)
+
+// KeyStrategy - Kafka 메시지 키로 쓸 값 (키가 같으면 같은 파티션 → 순서 보장)
+type KeyStrategy string
+
+const (
+    // KeyByOrderID - 주문 ID (CloudEvents subject), 한 주문의 이벤트는 발행 순서대로 소비됨
+    KeyByOrderID KeyStrategy = "order_id"
+    // KeyByEventID - 이벤트 ID, 파티션에 고르게 퍼지지만 순서는 보장되지 않음 (기존 방식)
+    KeyByEventID KeyStrategy = "event_id"
+    // KeyByProductID - data의 product_id (없으면 주문 ID), 상품별 재고 처리 순서 보장
+    KeyByProductID KeyStrategy = "product_id"
+)
+
+func (s KeyStrategy) valid() bool {
+    switch s {
+    case KeyByOrderID, KeyByEventID, KeyByProductID:
+        return true
+    }
+    return false
+}
+
+// Keying - 이벤트 타입별 키 전략 (지정하지 않은 타입은 Default)
+type Keying struct {
+    Default   KeyStrategy
+    Overrides map[string]KeyStrategy
+}
+
+// ParseKeying - 기본 전략과 "타입=전략,타입=전략" 형식의 타입별 전략
+//
+//	ParseKeying("order_id", "StockDeduction=product_id")
+func ParseKeying(defaultStrategy, overrides string) (Keying, error) {
+    k := Keying{
+        Default:   KeyStrategy(defaultStrategy),
+        Overrides: make(map[string]KeyStrategy),
+    }
+    if k.Default == "" {
+        k.Default = KeyByOrderID
+    }
+    if !k.Default.valid() {
+        return k, fmt.Errorf("unknown key strategy %q", defaultStrategy)
+    }
+
+    for _, entry := range strings.Split(overrides, ",") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        eventType, strategy, ok := strings.Cut(entry, "=")
+        eventType = strings.TrimSpace(eventType)
+        s := KeyStrategy(strings.TrimSpace(strategy))
+        if !ok || eventType == "" || !s.valid() {
+            return k, fmt.Errorf("invalid key strategy override %q", entry)
+        }
+        if DefaultRegistry.LatestVersion(eventType) == 0 {
+            return k, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+        }
+        k.Overrides[eventType] = s
+    }
+    return k, nil
+}
+
+func (k Keying) strategy(eventType string) KeyStrategy {
+    if s, ok := k.Overrides[eventType]; ok {
+        return s
+    }
+    if k.Default == "" {
+        return KeyByOrderID
+    }
+    return k.Default
+}
+
+// key - 봉투에서 전략에 맞는 키를 꺼냄, 값이 없으면 fallback (아웃박스에 기록된 키)
+func (k Keying) key(ce *CloudEvent, fallback string) string {
+    var key string
+    switch k.strategy(ce.EventType()) {
+    case KeyByEventID:
+        key = ce.ID
+    case KeyByProductID:
+        var data struct {
+            ProductID string `json:"product_id"`
+        }
+        _ = json.Unmarshal(ce.Data, &data)
+        key = data.ProductID
+        if key == "" {
+            key = ce.Subject
+        }
+    default:
+        key = ce.Subject
+    }
+    if key == "" {
+        return fallback
+    }
+    return key
+}
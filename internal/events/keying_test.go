@@ -0,0 +1,148 @@
+package events
+
+import (
+    "context"
+    "encoding/json"
+    "strconv"
+    "testing"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// emitted - 테스트에서 발행 순서대로 만든 이벤트
+type emitted struct {
+    orderID int
+    eventID string
+    msg     kafka.Message
+}
+
+func encodeEvent(t *testing.T, enc *messageEncoder, eventType string, orderID int, event interface{}) emitted {
+    t.Helper()
+    ce, err := NewCloudEvent(eventType, "", event)
+    if err != nil {
+        t.Fatalf("NewCloudEvent(%s): %v", eventType, err)
+    }
+    payload, err := json.Marshal(ce)
+    if err != nil {
+        t.Fatalf("marshal %s: %v", eventType, err)
+    }
+    msg, err := enc.encode(context.Background(), strconv.Itoa(orderID), payload)
+    if err != nil {
+        t.Fatalf("encode %s: %v", eventType, err)
+    }
+    return emitted{orderID: orderID, eventID: ce.ID, msg: msg}
+}
+
+// orderLifecycle - 한 주문이 생성부터 확정까지 내보내는 이벤트 (상품별 재고 차감 포함)
+func orderLifecycle(t *testing.T, enc *messageEncoder, orderID int) []emitted {
+    now := time.Now()
+    id := func(suffix string) string { return strconv.Itoa(orderID) + "-" + suffix }
+    return []emitted{
+        encodeEvent(t, enc, EventTypeOrderCreated, orderID, OrderCreatedEvent{
+            EventID: id("created"), OrderID: orderID, UserID: "user-1", Status: "PENDING", Timestamp: now,
+        }),
+        encodeEvent(t, enc, EventTypeStockDeduction, orderID, StockDeductionEvent{
+            EventID: id("deduct-a"), OrderID: orderID, ProductID: "PROD-A", Quantity: 1, Timestamp: now,
+        }),
+        encodeEvent(t, enc, EventTypeStockDeduction, orderID, StockDeductionEvent{
+            EventID: id("deduct-b"), OrderID: orderID, ProductID: "PROD-B", Quantity: 2, Timestamp: now,
+        }),
+        encodeEvent(t, enc, EventTypeOrderStatusChanged, orderID, OrderStatusChangedEvent{
+            EventID: id("confirmed"), OrderID: orderID, UserID: "user-1",
+            FromStatus: "PENDING", ToStatus: "CONFIRMED", Timestamp: now,
+        }),
+    }
+}
+
+func TestOrderEventsKeepEmitOrderInOnePartition(t *testing.T) {
+    keying, err := ParseKeying(string(KeyByOrderID), "")
+    if err != nil {
+        t.Fatal(err)
+    }
+    enc, err := newMessageEncoder(TopicOrderEvents, Serialization{Mode: ContentModeStructured, Keying: keying})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 여러 주문의 이벤트가 섞여서 발행되는 상황
+    orders := []int{1001, 1002, 1003, 1004}
+    lifecycles := make([][]emitted, len(orders))
+    for i, orderID := range orders {
+        lifecycles[i] = orderLifecycle(t, enc, orderID)
+    }
+    var stream []emitted
+    for step := range lifecycles[0] {
+        for i := range orders {
+            stream = append(stream, lifecycles[i][step])
+        }
+    }
+
+    partitions := make([]int, 12)
+    for i := range partitions {
+        partitions[i] = i
+    }
+    balancer := &kafka.Murmur2Balancer{}
+    logs := make(map[int][]emitted)
+    partitionOf := make(map[int]int)
+    for _, e := range stream {
+        p := balancer.Balance(e.msg, partitions...)
+        if want, ok := partitionOf[e.orderID]; ok && p != want {
+            t.Fatalf("order %d: event %s went to partition %d, earlier events went to %d", e.orderID, e.eventID, p, want)
+        }
+        partitionOf[e.orderID] = p
+        logs[p] = append(logs[p], e)
+    }
+
+    // 파티션을 처음부터 읽으면 주문별로 발행한 순서 그대로
+    for i, orderID := range orders {
+        var got []string
+        for _, e := range logs[partitionOf[orderID]] {
+            if e.orderID == orderID {
+                got = append(got, e.eventID)
+            }
+        }
+        if len(got) != len(lifecycles[i]) {
+            t.Fatalf("order %d: got %d events in partition, want %d", orderID, len(got), len(lifecycles[i]))
+        }
+        for j, e := range lifecycles[i] {
+            if got[j] != e.eventID {
+                t.Errorf("order %d: event %d is %s, want %s", orderID, j, got[j], e.eventID)
+            }
+        }
+    }
+}
+
+func TestKeyingOverrides(t *testing.T) {
+    keying, err := ParseKeying(string(KeyByOrderID), "StockDeduction=product_id, OrderCreated=event_id")
+    if err != nil {
+        t.Fatal(err)
+    }
+    enc, err := newMessageEncoder(TopicOrderEvents, Serialization{Mode: ContentModeBinary, Keying: keying})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    events := orderLifecycle(t, enc, 42)
+    want := []string{"42-created", "PROD-A", "PROD-B", "42"}
+    for i, e := range events {
+        if got := string(e.msg.Key); got != want[i] {
+            t.Errorf("event %d key = %q, want %q", i, got, want[i])
+        }
+    }
+}
+
+func TestParseKeyingRejectsInvalidOverrides(t *testing.T) {
+    for _, overrides := range []string{
+        "StockDeduction",
+        "StockDeduction=customer_id",
+        "NoSuchEvent=order_id",
+    } {
+        if _, err := ParseKeying(string(KeyByOrderID), overrides); err == nil {
+            t.Errorf("ParseKeying(%q) succeeded, want error", overrides)
+        }
+    }
+    if _, err := ParseKeying("random", ""); err == nil {
+        t.Error("ParseKeying with unknown default succeeded, want error")
+    }
+}
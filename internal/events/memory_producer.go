@@ -0,0 +1,62 @@
+package events
+
+import (
+    "context"
+    "sync"
+
+    "go.uber.org/zap"
+)
+
+// MemoryMessage - 인메모리 발행기에 기록된 메시지
+type MemoryMessage struct {
+    Key     string
+    Payload []byte
+}
+
+// MemoryPublisher - Kafka 없이 로컬 실행/테스트할 때 쓰는 발행기, 발행된 메시지를 순서대로 보관
+type MemoryPublisher struct {
+    topic    string
+    mu       sync.Mutex
+    messages []MemoryMessage
+    logger   *zap.Logger
+}
+
+func NewMemoryPublisher(topic string, logger *zap.Logger) *MemoryPublisher {
+    return &MemoryPublisher{
+        topic:  topic,
+        logger: logger,
+    }
+}
+
+func (p *MemoryPublisher) PublishMessage(ctx context.Context, key string, payload []byte) error {
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+
+    p.mu.Lock()
+    p.messages = append(p.messages, MemoryMessage{
+        Key:     key,
+        Payload: append([]byte(nil), payload...),
+    })
+    p.mu.Unlock()
+
+    p.logger.Info("Event published to memory",
+        zap.String("topic", p.topic),
+        zap.String("key", key))
+    return nil
+}
+
+// Messages - 지금까지 발행된 메시지 복사본
+func (p *MemoryPublisher) Messages() []MemoryMessage {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    return append([]MemoryMessage(nil), p.messages...)
+}
+
+func (p *MemoryPublisher) HealthCheck() error {
+    return nil
+}
+
+func (p *MemoryPublisher) Close() error {
+    return nil
+}
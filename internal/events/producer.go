@@ -4,60 +4,78 @@ import (
     "context"
     "encoding/json"
     "fmt"
+    "strconv"
     "time"
     
     "github.com/segmentio/kafka-go"
     "go.uber.org/zap"
 )
 
+// EventPublisher - structured CloudEvent JSON을 토픽 하나에 발행 (Kafka / 인메모리 구현)
+type EventPublisher interface {
+    PublishMessage(ctx context.Context, key string, payload []byte) error
+    HealthCheck() error
+    Close() error
+}
+
 type KafkaProducer struct {
-    writer *kafka.Writer
-    logger *zap.Logger
+    writer  *kafka.Writer
+    encoder *messageEncoder
+    health  *KafkaHealthChecker
+    logger  *zap.Logger
 }
 
 
 
-func NewKafkaProducer(brokers string, logger *zap.Logger) (*KafkaProducer, error) {
+func NewKafkaProducer(conn KafkaConnection, ser Serialization, health *KafkaHealthChecker, logger *zap.Logger) (*KafkaProducer, error) {
+    encoder, err := newMessageEncoder(TopicOrderEvents, ser)
+    if err != nil {
+        return nil, err
+    }
+
     writer := &kafka.Writer{
-        Addr:     kafka.TCP(brokers),
-        Topic:    "order-events",
-        Balancer: &kafka.LeastBytes{},
+        Addr:      kafka.TCP(conn.Brokers...),
+        Transport: conn.transport(),
+        Topic:     TopicOrderEvents,
+        Balancer:  &kafka.Murmur2Balancer{}, // 같은 키는 같은 파티션 (Java 클라이언트 기본 파티셔너와 같은 해시)
         BatchTimeout: 10 * time.Millisecond,
     }
     
     return &KafkaProducer{
-        writer: writer,
-        logger: logger,
+        writer:  writer,
+        encoder: encoder,
+        health:  health,
+        logger:  logger,
     }, nil
 }
 
+// HealthCheck - 브로커에 직접 연결해 토픽 메타데이터를 확인 (결과는 짧게 캐시)
 func (p *KafkaProducer) HealthCheck() error {
     if p.writer == nil {
         return fmt.Errorf("kafka writer not initialized")
     }
+    if p.health != nil {
+        return p.health.HealthCheck()
+    }
     return nil
 }
 
 func (p *KafkaProducer) PublishOrderCreated(event OrderCreatedEvent) error {
-    eventBytes, err := json.Marshal(event)
+    ce, err := NewCloudEvent(EventTypeOrderCreated, event.EventID, event)
     if err != nil {
         p.logger.Error("Failed to marshal event", zap.Error(err))
         return err
     }
-    
-    msg := kafka.Message{
-        Key:   []byte(event.EventID),
-        Value: eventBytes,
+    eventBytes, err := json.Marshal(ce)
+    if err != nil {
+        p.logger.Error("Failed to marshal event", zap.Error(err))
+        return err
     }
     
     ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
     defer cancel()
     
-    err = p.writer.WriteMessages(ctx, msg)
-    if err != nil {
-        p.logger.Error("Failed to publish message", 
-            zap.String("event_id", event.EventID),
-            zap.Error(err))
+    if err := p.PublishMessage(ctx, strconv.Itoa(event.OrderID), eventBytes); err != nil {
         return err
     }
     
@@ -68,6 +86,26 @@ func (p *KafkaProducer) PublishOrderCreated(event OrderCreatedEvent) error {
     return nil
 }
 
+// PublishMessage - 이미 직렬화된 CloudEvent를 설정된 코덱과 모드(structured/binary)로 발행 (아웃박스 릴레이용)
+func (p *KafkaProducer) PublishMessage(ctx context.Context, key string, payload []byte) error {
+    msg, err := p.encoder.encode(ctx, key, payload)
+    if err != nil {
+        return err
+    }
+    
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+    
+    if err := p.writer.WriteMessages(ctx, msg); err != nil {
+        p.logger.Error("Failed to publish message", 
+            zap.String("key", key),
+            zap.Error(err))
+        return err
+    }
+    
+    return nil
+}
+
 func (p *KafkaProducer) Close() error {
     if p.writer != nil {
         return p.writer.Close()
@@ -0,0 +1,134 @@
+package events
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "reflect"
+    "sync"
+)
+
+var (
+    ErrUnknownEventType         = errors.New("unknown event type")
+    ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+)
+
+// Upcaster - 한 버전의 data JSON을 다음 버전(+1) 형식으로 변환
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+type schemaKey struct {
+    eventType string
+    version   int
+}
+
+// Registry - 이벤트 타입+스키마 버전별 Go 구조체와 버전 간 업캐스터
+type Registry struct {
+    mu        sync.RWMutex
+    schemas   map[schemaKey]reflect.Type
+    latest    map[string]int
+    upcasters map[schemaKey]Upcaster
+}
+
+func NewRegistry() *Registry {
+    return &Registry{
+        schemas:   make(map[schemaKey]reflect.Type),
+        latest:    make(map[string]int),
+        upcasters: make(map[schemaKey]Upcaster),
+    }
+}
+
+// Register - prototype(구조체 값)의 타입을 eventType의 version 스키마로 등록, 가장 높은 버전이 발행 버전
+func (r *Registry) Register(eventType string, version int, prototype interface{}) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    t := reflect.TypeOf(prototype)
+    if t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+    r.schemas[schemaKey{eventType, version}] = t
+    if version > r.latest[eventType] {
+        r.latest[eventType] = version
+    }
+}
+
+// RegisterUpcaster - fromVersion 데이터를 fromVersion+1 형식으로 바꾸는 업캐스터 등록
+func (r *Registry) RegisterUpcaster(eventType string, fromVersion int, fn Upcaster) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.upcasters[schemaKey{eventType, fromVersion}] = fn
+}
+
+// LatestVersion - 발행에 쓰는 최신 스키마 버전 (등록되지 않은 타입이면 0)
+func (r *Registry) LatestVersion(eventType string) int {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    return r.latest[eventType]
+}
+
+// latestVersions - 등록된 이벤트 타입별 최신 버전 복사본
+func (r *Registry) latestVersions() map[string]int {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    versions := make(map[string]int, len(r.latest))
+    for eventType, version := range r.latest {
+        versions[eventType] = version
+    }
+    return versions
+}
+
+// DecodeVersion - data를 지정한 버전의 구조체로 그대로 디코딩 (업캐스트 없음)
+func (r *Registry) DecodeVersion(eventType string, version int, data []byte) (interface{}, error) {
+    r.mu.RLock()
+    t, ok := r.schemas[schemaKey{eventType, version}]
+    r.mu.RUnlock()
+    if !ok {
+        return nil, fmt.Errorf("%w: %s v%d", ErrUnsupportedSchemaVersion, eventType, version)
+    }
+
+    v := reflect.New(t)
+    if err := json.Unmarshal(data, v.Interface()); err != nil {
+        return nil, fmt.Errorf("invalid %s v%d payload: %w", eventType, version, err)
+    }
+    return v.Interface(), nil
+}
+
+// Upcast - version의 data를 최신 버전 형식으로 변환하고 최신 버전 번호를 반환
+// 버전이 0이면(봉투 없는 기존 메시지) 1로 간주
+func (r *Registry) Upcast(eventType string, version int, data json.RawMessage) (json.RawMessage, int, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    latest, ok := r.latest[eventType]
+    if !ok {
+        return nil, 0, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+    }
+    if version == 0 {
+        version = 1
+    }
+    if version > latest {
+        return nil, 0, fmt.Errorf("%w: %s v%d (latest v%d)", ErrUnsupportedSchemaVersion, eventType, version, latest)
+    }
+
+    for ; version < latest; version++ {
+        up, ok := r.upcasters[schemaKey{eventType, version}]
+        if !ok {
+            return nil, 0, fmt.Errorf("%w: no upcaster for %s v%d", ErrUnsupportedSchemaVersion, eventType, version)
+        }
+        var err error
+        if data, err = up(data); err != nil {
+            return nil, 0, fmt.Errorf("failed to upcast %s v%d: %w", eventType, version, err)
+        }
+    }
+    return data, latest, nil
+}
+
+// Decode - CloudEvent의 data를 최신 버전으로 업캐스트해 해당 구조체 포인터로 반환
+func (r *Registry) Decode(ce *CloudEvent) (interface{}, error) {
+    eventType := ce.EventType()
+    data, version, err := r.Upcast(eventType, ce.SchemaVersion, ce.Data)
+    if err != nil {
+        return nil, err
+    }
+    return r.DecodeVersion(eventType, version, data)
+}
@@ -0,0 +1,100 @@
+package events
+
+import (
+    "encoding/json"
+    "fmt"
+    "time"
+
+    "github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+// 스키마 버전 이력
+//   OrderCreated v1 - total_amount, items[].price가 통화 없는 실수 (Money 도입 전)
+//   OrderCreated v2 - {"amount", "currency"} Money 객체, line_id/catalog_version 추가
+// 나머지 이벤트는 v1 (필드 추가는 선택 필드로만 해 왔으므로 버전을 올리지 않음)
+
+// DefaultRegistry - 발행/수신하는 모든 이벤트 스키마
+var DefaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+    r := NewRegistry()
+
+    r.Register(EventTypeOrderCreated, 1, OrderCreatedEventV1{})
+    r.Register(EventTypeOrderCreated, 2, OrderCreatedEvent{})
+    r.RegisterUpcaster(EventTypeOrderCreated, 1, upcastOrderCreatedV1)
+
+    r.Register(EventTypeOrderStatusChanged, 1, OrderStatusChangedEvent{})
+    r.Register(EventTypeOrderLinesCancelled, 1, OrderLinesCancelledEvent{})
+    r.Register(EventTypeOrderAmended, 1, OrderAmendedEvent{})
+    r.Register(EventTypeStockDeduction, 1, StockDeductionEvent{})
+    r.Register(EventTypeCompensation, 1, CompensationEvent{})
+
+    // Product Service가 발행하는 재고 처리 결과
+    r.Register(EventTypeStockDeducted, 1, StockResultEvent{})
+    r.Register(EventTypeStockDeductionFailed, 1, StockResultEvent{})
+    return r
+}
+
+// OrderCreatedEventV1 - Money 도입 전 OrderCreated 스키마
+type OrderCreatedEventV1 struct {
+    EventID        string        `json:"event_id"`
+    OrderID        int           `json:"order_id"`
+    UserID         string        `json:"user_id"`
+    TotalAmount    float64       `json:"total_amount"`
+    Items          []OrderItemV1 `json:"items"`
+    Status         string        `json:"status"`
+    Timestamp      time.Time     `json:"timestamp"`
+    RequestID      string        `json:"request_id"`
+    IdempotencyKey string        `json:"idempotency_key"`
+    UserAgent      string        `json:"user_agent"`
+    SourceIP       string        `json:"source_ip"`
+}
+
+type OrderItemV1 struct {
+    ProductID   string  `json:"product_id"`
+    ProductName string  `json:"product_name"`
+    Quantity    int     `json:"quantity"`
+    Price       float64 `json:"price"`
+}
+
+// upcastOrderCreatedV1 - 실수 금액을 기본 통화(DEFAULT_CURRENCY)의 Money 객체로 변환
+// 이미 객체인 금액은 그대로 두므로 봉투 없는 v2 메시지에 적용해도 안전
+func upcastOrderCreatedV1(data json.RawMessage) (json.RawMessage, error) {
+    var event map[string]json.RawMessage
+    if err := json.Unmarshal(data, &event); err != nil {
+        return nil, err
+    }
+
+    var err error
+    if event["total_amount"], err = upcastAmount(event["total_amount"]); err != nil {
+        return nil, err
+    }
+
+    if raw, ok := event["items"]; ok && string(raw) != "null" {
+        var items []map[string]json.RawMessage
+        if err := json.Unmarshal(raw, &items); err != nil {
+            return nil, err
+        }
+        for _, item := range items {
+            if item["price"], err = upcastAmount(item["price"]); err != nil {
+                return nil, err
+            }
+        }
+        if event["items"], err = json.Marshal(items); err != nil {
+            return nil, err
+        }
+    }
+    return json.Marshal(event)
+}
+
+func upcastAmount(raw json.RawMessage) (json.RawMessage, error) {
+    var n json.Number
+    if len(raw) == 0 || json.Unmarshal(raw, &n) != nil {
+        return raw, nil
+    }
+    money, err := domain.ParseMoney(n.String(), domain.DefaultCurrency())
+    if err != nil {
+        return nil, fmt.Errorf("invalid legacy amount %s: %w", raw, err)
+    }
+    return json.Marshal(money)
+}
@@ -0,0 +1,111 @@
+package events
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sync"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// Serialization - 발행 메시지 직렬화 설정
+type Serialization struct {
+    Mode ContentMode
+    // Codec - data 직렬화 (nil이면 JSON)
+    Codec Codec
+    // Registry - 스키마가 있는 코덱이면 필수, 발행 전에 스키마를 등록해 하위 호환성을 확인
+    Registry SchemaRegistry
+    // Keying - 이벤트 타입별 메시지 키 (기본: 주문 ID)
+    Keying Keying
+}
+
+// messageEncoder - 아웃박스의 structured JSON 봉투를 키 전략, 코덱, 모드에 맞는 Kafka 메시지로 변환
+type messageEncoder struct {
+    topic string
+    ser   Serialization
+
+    mu         sync.Mutex
+    registered map[string]RegisteredSchema
+}
+
+func newMessageEncoder(topic string, ser Serialization) (*messageEncoder, error) {
+    if ser.Codec == nil {
+        ser.Codec = jsonCodec{}
+    }
+    if ser.Codec.Name() != CodecJSON && ser.Registry == nil {
+        return nil, fmt.Errorf("%s codec requires a schema registry", ser.Codec.Name())
+    }
+    return &messageEncoder{
+        topic:      topic,
+        ser:        ser,
+        registered: make(map[string]RegisteredSchema),
+    }, nil
+}
+
+// encode - key는 아웃박스에 기록된 키로, 키 전략에 맞는 값이 봉투에 없을 때만 사용
+func (e *messageEncoder) encode(ctx context.Context, key string, payload []byte) (kafka.Message, error) {
+    var ce CloudEvent
+    if err := json.Unmarshal(payload, &ce); err != nil {
+        return kafka.Message{}, fmt.Errorf("invalid cloudevent payload: %w", err)
+    }
+    key = e.ser.Keying.key(&ce, key)
+
+    if e.ser.Codec.Name() == CodecJSON {
+        if e.ser.Mode == ContentModeBinary {
+            return binaryMessage(key, &ce, ce.Data), nil
+        }
+        return structuredMessage(key, payload), nil
+    }
+
+    eventType := ce.EventType()
+    encoded, err := e.ser.Codec.Encode(eventType, ce.SchemaVersion, ce.Data)
+    if err != nil {
+        return kafka.Message{}, err
+    }
+    schema, err := e.register(ctx, &ce)
+    if err != nil {
+        return kafka.Message{}, err
+    }
+    data := encodeWire(e.ser.Codec.Name(), schema.ID, encoded)
+
+    ce.DataContentType = e.ser.Codec.ContentType()
+    ce.DataSchema = schema.URI
+    if e.ser.Mode == ContentModeBinary {
+        return binaryMessage(key, &ce, data), nil
+    }
+
+    ce.Data = nil
+    ce.DataBase64 = data
+    value, err := json.Marshal(&ce)
+    if err != nil {
+        return kafka.Message{}, err
+    }
+    return structuredMessage(key, value), nil
+}
+
+// register - 처음 발행하는 subject면 레지스트리에 스키마를 등록 (호환되지 않으면 발행 중단)
+// 메이저 버전(schemaversion)마다 subject가 달라 하위 호환은 같은 버전 안에서만 요구됨
+//
+//	<topic>-<type>.v<schemaversion>  (예: order-events-com.cloudwave.order.OrderCreated.v2)
+func (e *messageEncoder) register(ctx context.Context, ce *CloudEvent) (RegisteredSchema, error) {
+    version := codecVersion(ce.SchemaVersion)
+    subject := fmt.Sprintf("%s-%s.v%d", e.topic, ce.Type, version)
+
+    e.mu.Lock()
+    defer e.mu.Unlock()
+    if schema, ok := e.registered[subject]; ok {
+        return schema, nil
+    }
+
+    schema, err := e.ser.Codec.Schema(ce.EventType(), version)
+    if err != nil {
+        return RegisteredSchema{}, err
+    }
+    registered, err := e.ser.Registry.Register(ctx, subject, *schema)
+    if err != nil {
+        return RegisteredSchema{}, fmt.Errorf("failed to register schema: %w", err)
+    }
+    e.registered[subject] = registered
+    return registered, nil
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+)
+
+// Confluent 스키마 레지스트리 wire format - Confluent 직렬화기를 쓰는 소비자도 읽을 수 있도록 data 앞에 붙임
+//
+//	0x00 | 스키마 ID (4바이트 빅엔디언) | [Protobuf만: 메시지 인덱스] | 직렬화된 data
+//
+// 내장 .proto 파일은 이벤트 메시지가 첫 번째이므로 메시지 인덱스는 항상 [0]이며, 규약대로 0 한 바이트로 줄여 씀
+const (
+    wireMagicByte  byte = 0
+    wireHeaderSize      = 5
+)
+
+var ErrWireFormat = errors.New("invalid schema registry wire format")
+
+// encodeWire - 레지스트리에 등록된 schemaID로 data를 감쌈
+func encodeWire(codec string, schemaID int, data []byte) []byte {
+    out := make([]byte, wireHeaderSize, wireHeaderSize+1+len(data))
+    out[0] = wireMagicByte
+    binary.BigEndian.PutUint32(out[1:wireHeaderSize], uint32(schemaID))
+    if codec == CodecProtobuf {
+        out = append(out, 0)
+    }
+    return append(out, data...)
+}
+
+// decodeWire - wire format 헤더를 검사해 스키마 ID와 data를 분리
+func decodeWire(codec string, payload []byte) (int, []byte, error) {
+    if len(payload) < wireHeaderSize || payload[0] != wireMagicByte {
+        return 0, nil, fmt.Errorf("%w: missing magic byte", ErrWireFormat)
+    }
+    schemaID := int(binary.BigEndian.Uint32(payload[1:wireHeaderSize]))
+    data := payload[wireHeaderSize:]
+    if codec != CodecProtobuf {
+        return schemaID, data, nil
+    }
+
+    // 메시지 인덱스 - 개수 다음 인덱스들 (모두 zigzag varint), 개수 0은 [0]
+    // 파일의 첫 번째 최상위 메시지([0])만 허용
+    count, n := binary.Varint(data)
+    if n <= 0 || count < 0 {
+        return 0, nil, fmt.Errorf("%w: invalid protobuf message index", ErrWireFormat)
+    }
+    data = data[n:]
+    if count == 0 {
+        return schemaID, data, nil
+    }
+    index, n := binary.Varint(data)
+    if n <= 0 {
+        return 0, nil, fmt.Errorf("%w: invalid protobuf message index", ErrWireFormat)
+    }
+    if count != 1 || index != 0 {
+        return 0, nil, fmt.Errorf("%w: unsupported protobuf message index", ErrWireFormat)
+    }
+    return schemaID, data[n:], nil
+}
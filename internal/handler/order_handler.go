@@ -3,28 +3,94 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cloud-wave-best-zizon/order-service/internal/catalog"
 	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
 	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/order-service/internal/saga"
 	"github.com/cloud-wave-best-zizon/order-service/internal/service"
+	"github.com/cloud-wave-best-zizon/order-service/internal/statemachine"
+	"github.com/cloud-wave-best-zizon/order-service/pkg/cursor"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// OrderModifier - 재고 차감 상태를 함께 봐야 하는 주문 변경 (취소, 부분 취소, 항목 변경)
+type OrderModifier interface {
+	CancelOrder(ctx context.Context, orderID int, reason string) (*domain.Order, error)
+	CancelLines(ctx context.Context, orderID int, lines []domain.CancelLine, reason string) (*domain.Order, error)
+	AmendOrder(ctx context.Context, orderID, expectedVersion int, changes []domain.ItemChange, reason string) (*domain.Order, error)
+}
+
 type OrderHandler struct {
 	orderService *service.OrderService
+	modifier     OrderModifier
+	cursors      *cursor.Signer
 	logger       *zap.Logger
 }
 
-func NewOrderHandler(orderService *service.OrderService, logger *zap.Logger) *OrderHandler {
+func NewOrderHandler(orderService *service.OrderService, modifier OrderModifier, cursors *cursor.Signer, logger *zap.Logger) *OrderHandler {
 	return &OrderHandler{
 		orderService: orderService,
+		modifier:     modifier,
+		cursors:      cursors,
 		logger:       logger,
 	}
 }
 
+// userOrdersCursor - 다른 사용자나 다른 조건의 목록에 재사용하지 못하도록 user_id와 필터를 함께 서명
+type userOrdersCursor struct {
+	UserID        string             `json:"u"`
+	Status        domain.OrderStatus `json:"s,omitempty"`
+	CreatedAfter  time.Time          `json:"a"`
+	CreatedBefore time.Time          `json:"b"`
+	Key           map[string]string  `json:"k"`
+}
+
+func newUserOrdersCursor(q repository.UserOrdersQuery, key map[string]string) userOrdersCursor {
+	return userOrdersCursor{
+		UserID:        q.UserID,
+		Status:        q.Status,
+		CreatedAfter:  q.CreatedAfter,
+		CreatedBefore: q.CreatedBefore,
+		Key:           key,
+	}
+}
+
+// matches - 커서를 만든 요청과 조건이 같은지
+func (cur userOrdersCursor) matches(q repository.UserOrdersQuery) bool {
+	return cur.UserID == q.UserID && cur.Status == q.Status &&
+		cur.CreatedAfter.Equal(q.CreatedAfter) && cur.CreatedBefore.Equal(q.CreatedBefore)
+}
+
+// statusOrdersCursor - 다른 상태/정렬 방향/기간의 목록에 재사용하지 못하도록 조건을 함께 서명
+// older_than은 요청 시각마다 기준이 달라지므로 계산된 시각이 아니라 요청 값 그대로 서명
+type statusOrdersCursor struct {
+	Status        domain.OrderStatus `json:"s,omitempty"`
+	Descending    bool               `json:"d,omitempty"`
+	CreatedAfter  time.Time          `json:"a"`
+	CreatedBefore time.Time          `json:"b"`
+	OlderThan     string             `json:"o,omitempty"`
+	Key           map[string]string  `json:"k"`
+}
+
+// matches - 커서를 만든 요청과 조건이 같은지
+func (cur statusOrdersCursor) matches(other statusOrdersCursor) bool {
+	return cur.Status == other.Status && cur.Descending == other.Descending &&
+		cur.CreatedAfter.Equal(other.CreatedAfter) && cur.CreatedBefore.Equal(other.CreatedBefore) &&
+		cur.OlderThan == other.OlderThan
+}
+
 // internal/handler/order_handler.go의 CreateOrder 메서드
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	var req domain.CreateOrderRequest
@@ -41,13 +107,24 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	// Request ID from middleware
 	requestID := c.GetString("request_id")
 
-	// Context에 추가 정보 넣기
-	ctx := context.WithValue(c.Request.Context(), "user_agent", c.Request.UserAgent())
-	ctx = context.WithValue(ctx, "source_ip", c.ClientIP())
+	// Context에 요청 주체 정보 넣기 (X-Actor-ID가 없으면 주문자를 주장한 주체로 기록)
+	ctx := actorContext(c, req.UserID)
 
 	// Create order
-	order, err := h.orderService.CreateOrder(ctx, req, requestID)
+	result, err := h.orderService.CreateOrder(ctx, req, requestID)
 	if err != nil {
+		if errors.Is(err, service.ErrIdempotencyKeyReused) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "Idempotency key already used with a different request",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		if writePricingError(c, err, requestID) {
+			return
+		}
+
 		h.logger.Error("Failed to create order",
 			zap.String("request_id", requestID),
 			zap.Error(err))
@@ -60,13 +137,66 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	}
 
 	// Response
-	response := domain.CreateOrderResponse{
-		OrderID: order.OrderID,
-		Status:  order.Status,
-		Message: "Order created successfully",
+	if result.Replayed {
+		c.Header("Idempotent-Replayed", "true")
 	}
+	c.JSON(http.StatusCreated, result.Response)
+}
 
-	c.JSON(http.StatusCreated, response)
+// actorContext - 변경 이력에 남길 요청 주체를 담은 context
+// ID는 mTLS로 인증된 principal, 없으면 anonymous
+// 클라이언트가 보낸 X-Actor-ID(없으면 fallback)는 검증되지 않은 ClaimedID로만 기록
+func actorContext(c *gin.Context, fallback string) context.Context {
+	id := c.GetString("principal")
+	if id == "" {
+		id = "anonymous"
+	}
+	claimed := c.GetHeader("X-Actor-ID")
+	if claimed == "" {
+		claimed = fallback
+	}
+	return service.WithActor(c.Request.Context(), domain.Actor{
+		ID:        id,
+		ClaimedID: claimed,
+		RequestID: c.GetString("request_id"),
+		UserAgent: c.Request.UserAgent(),
+		SourceIP:  c.ClientIP(),
+	})
+}
+
+// writePricingError - 가격 카탈로그/금액 계산 에러면 응답을 쓰고 true 반환
+func writePricingError(c *gin.Context, err error, requestID string) bool {
+	var mismatch *service.PriceMismatchError
+	switch {
+	case errors.As(err, &mismatch):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":         "Quoted price does not match catalog price",
+			"product_id":    mismatch.ProductID,
+			"quoted_price":  mismatch.QuotedPrice,
+			"catalog_price": mismatch.CatalogPrice,
+			"request_id":    requestID,
+		})
+	case errors.Is(err, catalog.ErrProductNotFound):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":      "Unknown product",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+	case errors.Is(err, domain.ErrCurrencyMismatch), errors.Is(err, domain.ErrAmountOverflow):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":      "Order amount cannot be calculated",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+	case errors.Is(err, catalog.ErrCatalogUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":      "Price catalog unavailable",
+			"request_id": requestID,
+		})
+	default:
+		return false
+	}
+	return true
 }
 
 func (h *OrderHandler) GetOrder(c *gin.Context) {
@@ -87,5 +217,379 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		return
 	}
 
+	writeOrder(c, order)
+}
+
+// GetOrderHistory - GET /orders/:id/history, 주문 변경 이력 (오래된 순)
+func (h *OrderHandler) GetOrderHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	history, err := h.orderService.GetOrderHistory(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.OrderHistoryResponse{OrderID: id, History: history})
+}
+
+func (h *OrderHandler) TransitionOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req domain.TransitionOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !req.Status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status"})
+		return
+	}
+
+	// 취소는 재고 보상이 필요하므로 취소 API와 같은 경로로 처리
+	var order *domain.Order
+	if req.Status == domain.OrderStatusCancelled {
+		order, err = h.modifier.CancelOrder(actorContext(c, ""), id, req.Reason)
+	} else {
+		order, err = h.orderService.Transition(actorContext(c, ""), id, req.Status, req.Reason, nil)
+	}
+	if err != nil {
+		writeTransitionError(c, err)
+		return
+	}
+
+	writeOrder(c, order)
+}
+
+// CancelOrder - POST /orders/:id/cancel, 이미 취소된 주문은 보상만 다시 시도하고 200 반환
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req domain.CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !req.ReasonCode.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown reason_code"})
+		return
+	}
+
+	order, err := h.modifier.CancelOrder(actorContext(c, ""), id, cancelReason(req.ReasonCode, req.Reason))
+	if err != nil {
+		writeTransitionError(c, err)
+		return
+	}
+
+	writeOrder(c, order)
+}
+
+// CancelOrderLines - POST /orders/:id/lines/cancel, 항목별 수량 부분 취소
+func (h *OrderHandler) CancelOrderLines(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req domain.CancelLinesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !req.ReasonCode.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown reason_code"})
+		return
+	}
+
+	order, err := h.modifier.CancelLines(actorContext(c, ""), id, req.Lines, cancelReason(req.ReasonCode, req.Reason))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLineNotFound), errors.Is(err, service.ErrInvalidLineQuantity):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrCancelsWholeOrder):
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "every remaining line would be cancelled, use POST /orders/:id/cancel instead",
+			})
+		default:
+			writeTransitionError(c, err)
+		}
+		return
+	}
+
+	writeOrder(c, order)
+}
+
+// AmendOrder - PATCH /orders/:id, If-Match의 버전이 현재 주문 버전과 같을 때만 항목 변경
+func (h *OrderHandler) AmendOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header with the order ETag is required"})
+		return
+	}
+	version, err := parseETag(ifMatch)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header"})
+		return
+	}
+
+	var req domain.AmendOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	requestID := c.GetString("request_id")
+	order, err := h.modifier.AmendOrder(actorContext(c, ""), id, version, req.Changes, req.Reason)
+	if err != nil {
+		if writePricingError(c, err, requestID) {
+			return
+		}
+		switch {
+		case errors.Is(err, repository.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		case errors.Is(err, service.ErrVersionMismatch):
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrVersionConflict), errors.Is(err, repository.ErrSagaVersionConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotAmendable), errors.Is(err, saga.ErrAmendmentClosed),
+			errors.Is(err, service.ErrCancelsWholeOrder):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidChange), errors.Is(err, service.ErrLineNotFound):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to amend order",
+				zap.String("request_id", requestID),
+				zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	writeOrder(c, order)
+}
+
+// writeOrder - 다음 변경 요청의 If-Match에 쓸 수 있도록 버전을 ETag로 함께 반환
+func writeOrder(c *gin.Context, order *domain.Order) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(order.Version)))
 	c.JSON(http.StatusOK, order)
-}
\ No newline at end of file
+}
+
+// parseETag - "3", W/"3", 3 형식 모두 허용
+func parseETag(v string) (int, error) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+	if unquoted, err := strconv.Unquote(v); err == nil {
+		v = unquoted
+	}
+	return strconv.Atoi(v)
+}
+
+// cancelReason - 사유 코드와 선택 설명을 이벤트/로그에 남길 한 줄로 합침
+func cancelReason(code domain.CancelReasonCode, detail string) string {
+	if detail == "" {
+		return string(code)
+	}
+	return string(code) + ": " + detail
+}
+
+func writeTransitionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+	case errors.Is(err, statemachine.ErrInvalidTransition),
+		errors.Is(err, repository.ErrStatusConflict),
+		errors.Is(err, repository.ErrVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// ListUserOrders - GET /users/:user_id/orders?limit=&cursor=&status=&created_after=&created_before=
+func (h *OrderHandler) ListUserOrders(c *gin.Context) {
+	q := repository.UserOrdersQuery{
+		UserID: c.Param("user_id"),
+		Limit:  defaultPageLimit,
+		Status: domain.OrderStatus(c.Query("status")),
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		q.Limit = int32(limit)
+	}
+	if q.Status != "" && !q.Status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status"})
+		return
+	}
+
+	var err error
+	if q.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if q.CreatedBefore, err = parseTimeQuery(c, "created_before"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if token := c.Query("cursor"); token != "" {
+		var cur userOrdersCursor
+		if err := h.cursors.Decode(token, &cur); err != nil || !cur.matches(q) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		q.StartKey = cur.Key
+	}
+
+	page, err := h.orderService.ListUserOrders(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := domain.ListOrdersResponse{Orders: page.Orders}
+	if page.NextKey != nil {
+		resp.NextCursor, err = h.cursors.Encode(newUserOrdersCursor(q, page.NextKey))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminListOrders - GET /admin/orders?status=&created_after=&created_before=&older_than=&order=&limit=&cursor=
+// older_than(예: 15m)은 created_before = 현재 - older_than 과 같고, 둘 다 주면 더 이른 시각을 사용
+func (h *OrderHandler) AdminListOrders(c *gin.Context) {
+	q := repository.StatusOrdersQuery{
+		Status: domain.OrderStatus(c.Query("status")),
+		Limit:  defaultPageLimit,
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		q.Limit = int32(limit)
+	}
+	if q.Status != "" && !q.Status.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status"})
+		return
+	}
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		q.Descending = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return
+	}
+
+	var err error
+	if q.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if q.CreatedBefore, err = parseTimeQuery(c, "created_before"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter := statusOrdersCursor{
+		Status:        q.Status,
+		Descending:    q.Descending,
+		CreatedAfter:  q.CreatedAfter,
+		CreatedBefore: q.CreatedBefore,
+		OlderThan:     c.Query("older_than"),
+	}
+	if v := filter.OlderThan; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "older_than must be a non-negative duration (e.g. 15m)"})
+			return
+		}
+		if cutoff := time.Now().Add(-d); q.CreatedBefore.IsZero() || cutoff.Before(q.CreatedBefore) {
+			q.CreatedBefore = cutoff
+		}
+	}
+
+	if token := c.Query("cursor"); token != "" {
+		var cur statusOrdersCursor
+		if err := h.cursors.Decode(token, &cur); err != nil || !cur.matches(filter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		q.StartKey = cur.Key
+	}
+
+	page, err := h.orderService.ListOrdersByStatus(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := domain.ListOrdersResponse{Orders: page.Orders}
+	if page.NextKey != nil {
+		filter.Key = page.NextKey
+		resp.NextCursor, err = h.cursors.Encode(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseTimeQuery - RFC3339 쿼리 파라미터 (없으면 zero time)
+func parseTimeQuery(c *gin.Context, name string) (time.Time, error) {
+	v := c.Query(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC3339 timestamp", name)
+	}
+	return t, nil
+}
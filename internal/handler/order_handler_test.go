@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/order-service/internal/service"
+	"github.com/cloud-wave-best-zizon/order-service/pkg/cursor"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func actorFor(principal, header, fallback string) domain.Actor {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/api/v1/orders", nil)
+	if header != "" {
+		c.Request.Header.Set("X-Actor-ID", header)
+	}
+	if principal != "" {
+		c.Set("principal", principal)
+	}
+	return service.ActorFrom(actorContext(c, fallback))
+}
+
+func TestActorContextDoesNotTrustHeader(t *testing.T) {
+	tests := []struct {
+		name                        string
+		principal, header, fallback string
+		want                        domain.Actor
+	}{
+		{"header only", "", "admin", "", domain.Actor{ID: "anonymous", ClaimedID: "admin"}},
+		{"order owner", "", "", "user-1", domain.Actor{ID: "anonymous", ClaimedID: "user-1"}},
+		{"authenticated", "spiffe://example.org/ns/ops/sa/admin", "admin", "",
+			domain.Actor{ID: "spiffe://example.org/ns/ops/sa/admin", ClaimedID: "admin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := actorFor(tt.principal, tt.header, tt.fallback)
+			if got.ID != tt.want.ID || got.ClaimedID != tt.want.ClaimedID {
+				t.Errorf("actor = %+v, want id %q claimed %q", got, tt.want.ID, tt.want.ClaimedID)
+			}
+		})
+	}
+}
+
+func TestListUserOrdersCursorBoundToFilters(t *testing.T) {
+	store := repository.NewMemoryOrderRepository()
+	base := time.Now().UTC().Add(-time.Hour)
+	for id := 1; id <= 3; id++ {
+		order := &domain.Order{
+			OrderID:   id,
+			UserID:    "user-1",
+			Items:     []domain.OrderItem{{LineID: 1, ProductID: "PROD-A", Quantity: 1, Status: domain.LineStatusActive}},
+			Status:    domain.OrderStatusPending,
+			CreatedAt: base.Add(time.Duration(id) * time.Minute),
+			UpdatedAt: base,
+			Version:   1,
+		}
+		if err := store.CreateOrder(context.Background(), order, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orders := service.NewOrderService(store, nil, nil, nil, service.Config{}, zap.NewNop())
+	h := NewOrderHandler(orders, nil, cursor.NewSigner([]byte("secret")), zap.NewNop())
+	router := gin.New()
+	router.GET("/users/:user_id/orders", h.ListUserOrders)
+
+	list := func(params url.Values) (int, domain.ListOrdersResponse) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users/user-1/orders?"+params.Encode(), nil))
+		var resp domain.ListOrdersResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Code, resp
+	}
+
+	filter := url.Values{"status": {"PENDING"}, "created_after": {base.Format(time.RFC3339)}, "limit": {"1"}}
+	code, first := list(filter)
+	if code != http.StatusOK || first.NextCursor == "" {
+		t.Fatalf("first page: status %d, cursor %q", code, first.NextCursor)
+	}
+
+	next := url.Values{"cursor": {first.NextCursor}}
+	for k, v := range filter {
+		next[k] = v
+	}
+	if code, _ := list(next); code != http.StatusOK {
+		t.Errorf("same filters: status %d, want 200", code)
+	}
+
+	changed := map[string]url.Values{
+		"status":         {"status": {"CONFIRMED"}},
+		"created_after":  {"created_after": {base.Add(time.Minute).Format(time.RFC3339)}},
+		"created_before": {"created_before": {base.Add(time.Hour).Format(time.RFC3339)}},
+	}
+	for name, override := range changed {
+		params := url.Values{"cursor": {first.NextCursor}}
+		for k, v := range filter {
+			params[k] = v
+		}
+		for k, v := range override {
+			params[k] = v
+		}
+		if code, _ := list(params); code != http.StatusBadRequest {
+			t.Errorf("%s changed: status %d, want 400", name, code)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IDGenerator - 주문 ID 발급기
+type IDGenerator interface {
+	NextID() (int, error)
+}
+
+// Snowflake 비트 구성: 41비트 타임스탬프(ms) | 10비트 노드 ID | 12비트 시퀀스
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+
+	MaxNodeID   = 1<<nodeBits - 1
+	maxSequence = 1<<sequenceBits - 1
+
+	// 시계가 이 이상 뒤로 가면 대기하지 않고 에러 반환
+	maxClockDrift = 5 * time.Second
+)
+
+// Epoch - 타임스탬프 기준 시각 (2024-01-01T00:00:00Z)
+var Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var ErrClockMovedBackwards = errors.New("clock moved backwards")
+
+// Snowflake - 노드(파드)별로 고유한 nodeID를 사용하면 충돌 없는 ID를 생성
+// ID는 2^53보다 커서 JSON 숫자를 float64로 읽는 클라이언트(JavaScript 등)는 정밀도를 잃으므로
+// 그런 클라이언트는 문자열로 다뤄야 함 (JSON.parse 대신 BigInt 지원 파서 등)
+type Snowflake struct {
+	mu       sync.Mutex
+	nodeID   int64
+	lastMs   int64
+	sequence int64
+	now      func() time.Time // 테스트에서 시계를 바꿀 때만 교체
+}
+
+func NewSnowflake(nodeID int) (*Snowflake, error) {
+	if nodeID < 0 || nodeID > MaxNodeID {
+		return nil, fmt.Errorf("node id must be between 0 and %d, got %d", MaxNodeID, nodeID)
+	}
+	return &Snowflake{
+		nodeID: int64(nodeID),
+		now:    time.Now,
+	}, nil
+}
+
+func (s *Snowflake) NextID() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := s.elapsedMs()
+	if ms < s.lastMs {
+		drift := time.Duration(s.lastMs-ms) * time.Millisecond
+		if drift > maxClockDrift {
+			return 0, fmt.Errorf("%w by %s", ErrClockMovedBackwards, drift)
+		}
+		time.Sleep(drift)
+		ms = s.elapsedMs()
+		if ms < s.lastMs {
+			return 0, fmt.Errorf("%w by %s", ErrClockMovedBackwards, drift)
+		}
+	}
+
+	if ms == s.lastMs {
+		s.sequence = (s.sequence + 1) & maxSequence
+		if s.sequence == 0 {
+			// 같은 밀리초의 시퀀스를 모두 사용하면 다음 밀리초까지 대기
+			for ms <= s.lastMs {
+				ms = s.elapsedMs()
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastMs = ms
+
+	return int(ms<<(nodeBits+sequenceBits) | s.nodeID<<sequenceBits | s.sequence), nil
+}
+
+func (s *Snowflake) elapsedMs() int64 {
+	return s.now().Sub(Epoch).Milliseconds()
+}
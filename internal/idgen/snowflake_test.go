@@ -0,0 +1,175 @@
+package idgen
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// clock - 호출마다 times를 차례로 반환하고, 다 쓰면 마지막 시각을 계속 반환
+func clock(times ...time.Time) func() time.Time {
+	var mu sync.Mutex
+	return func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		t := times[0]
+		if len(times) > 1 {
+			times = times[1:]
+		}
+		return t
+	}
+}
+
+func decode(id int) (ms, node, seq int64) {
+	v := int64(id)
+	return v >> (nodeBits + sequenceBits), v >> sequenceBits & MaxNodeID, v & maxSequence
+}
+
+func TestSnowflakeUniqueUnderConcurrency(t *testing.T) {
+	s, err := NewSnowflake(513)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const workers, perWorker = 8, 5000
+	ids := make(chan int, workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				id, err := s.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool, workers*perWorker)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+		if _, node, _ := decode(id); node != 513 {
+			t.Fatalf("id %d has node %d, want 513", id, node)
+		}
+	}
+	if len(seen) != workers*perWorker {
+		t.Errorf("generated %d ids, want %d", len(seen), workers*perWorker)
+	}
+}
+
+func TestSnowflakeSequenceExhaustion(t *testing.T) {
+	s, err := NewSnowflake(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := Epoch.Add(time.Hour)
+	// 한 밀리초에 시퀀스 4096개를 다 쓰고, 다음 ID는 시계가 넘어갈 때까지 기다림
+	times := make([]time.Time, maxSequence+2)
+	for i := range times {
+		times[i] = start
+	}
+	times = append(times, start.Add(time.Millisecond))
+	s.now = clock(times...)
+
+	prev := 0
+	for i := 0; i <= maxSequence; i++ {
+		id, err := s.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ms, _, seq := decode(id); ms != time.Hour.Milliseconds() || seq != int64(i) {
+			t.Fatalf("id %d = ms %d seq %d, want ms %d seq %d", i, ms, seq, time.Hour.Milliseconds(), i)
+		}
+		if id <= prev {
+			t.Fatalf("id %d not increasing", i)
+		}
+		prev = id
+	}
+
+	id, err := s.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms, _, seq := decode(id); ms != time.Hour.Milliseconds()+1 || seq != 0 {
+		t.Errorf("after exhaustion ms %d seq %d, want next millisecond with seq 0", ms, seq)
+	}
+	if id <= prev {
+		t.Error("id after exhaustion not increasing")
+	}
+}
+
+func TestSnowflakeClockMovedBackwards(t *testing.T) {
+	start := Epoch.Add(time.Hour)
+
+	t.Run("small drift waits", func(t *testing.T) {
+		s, err := NewSnowflake(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.now = clock(start, start.Add(-2*time.Millisecond), start)
+		first, err := s.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := s.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second <= first {
+			t.Errorf("id after drift %d <= %d", second, first)
+		}
+	})
+
+	t.Run("still behind after waiting", func(t *testing.T) {
+		s, err := NewSnowflake(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.now = clock(start, start.Add(-time.Millisecond))
+		if _, err := s.NextID(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.NextID(); !errors.Is(err, ErrClockMovedBackwards) {
+			t.Errorf("err = %v, want ErrClockMovedBackwards", err)
+		}
+	})
+
+	t.Run("large drift fails fast", func(t *testing.T) {
+		s, err := NewSnowflake(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.now = clock(start, start.Add(-maxClockDrift-time.Second))
+		if _, err := s.NextID(); err != nil {
+			t.Fatal(err)
+		}
+		began := time.Now()
+		if _, err := s.NextID(); !errors.Is(err, ErrClockMovedBackwards) {
+			t.Errorf("err = %v, want ErrClockMovedBackwards", err)
+		}
+		if waited := time.Since(began); waited > time.Second {
+			t.Errorf("waited %s before failing", waited)
+		}
+	})
+}
+
+func TestNewSnowflakeNodeID(t *testing.T) {
+	for _, id := range []int{-1, MaxNodeID + 1} {
+		if _, err := NewSnowflake(id); err == nil {
+			t.Errorf("node id %d accepted", id)
+		}
+	}
+	if _, err := NewSnowflake(MaxNodeID); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,229 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Store - 릴레이가 사용하는 아웃박스 저장소
+type Store interface {
+	ListPendingOutbox(ctx context.Context, now time.Time, limit int32) ([]*repository.OutboxMessage, error)
+	ClaimOutbox(ctx context.Context, msg *repository.OutboxMessage, until time.Time) (bool, error)
+	MarkOutboxSent(ctx context.Context, msg *repository.OutboxMessage, sentAt time.Time) error
+	ScheduleOutboxRetry(ctx context.Context, messageID string, attempts int, next time.Time, lastErr string) error
+}
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int32
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// 선점한 메시지를 다른 인스턴스가 다시 가져가기까지의 시간
+	ClaimTimeout time.Duration
+}
+
+// Relay - 대기 중인 아웃박스 메시지를 Kafka로 발행하는 백그라운드 워커
+type Relay struct {
+	store      Store
+	publishers map[string]events.EventPublisher
+	cfg        Config
+	notify     chan struct{}
+	logger     *zap.Logger
+}
+
+func NewRelay(store Store, cfg Config, logger *zap.Logger) *Relay {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 25
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = time.Second
+	}
+	if cfg.MaxBackoff < cfg.BaseBackoff {
+		cfg.MaxBackoff = cfg.BaseBackoff
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = 30 * time.Second
+	}
+
+	return &Relay{
+		store:      store,
+		publishers: make(map[string]events.EventPublisher),
+		cfg:        cfg,
+		notify:     make(chan struct{}, 1),
+		logger:     logger,
+	}
+}
+
+// Register - 토픽에 발행기 연결 (Run 호출 전에만 사용)
+func (r *Relay) Register(topic string, p events.EventPublisher) {
+	r.publishers[topic] = p
+}
+
+// HealthCheck - 등록된 모든 발행기의 상태 확인
+func (r *Relay) HealthCheck() error {
+	for topic, p := range r.publishers {
+		if err := p.HealthCheck(); err != nil {
+			return fmt.Errorf("%s: %w", topic, err)
+		}
+	}
+	return nil
+}
+
+// Notify - 새 메시지가 기록되었음을 알려 다음 폴링을 앞당김
+func (r *Relay) Notify() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run - ctx가 취소될 때까지 아웃박스를 폴링
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("Outbox relay started",
+		zap.Duration("poll_interval", r.cfg.PollInterval),
+		zap.Int32("batch_size", r.cfg.BatchSize))
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		case <-r.notify:
+		}
+	}
+}
+
+// drain - 배치의 메시지를 모두 선점한 동안 연속으로 처리
+// 다른 인스턴스가 선점했거나 키가 막혀 하나라도 선점하지 못했으면 다음 폴링까지 기다림
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := r.processBatch(ctx)
+		if err != nil {
+			r.logger.Error("Failed to process outbox batch", zap.Error(err))
+			return
+		}
+		if claimed < int(r.cfg.BatchSize) {
+			return
+		}
+	}
+}
+
+// processBatch - 선점한 메시지 수를 반환
+func (r *Relay) processBatch(ctx context.Context) (int, error) {
+	msgs, err := r.store.ListPendingOutbox(ctx, time.Now(), r.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// 발행에 실패한 메시지의 토픽/키 - 같은 배치의 뒤 메시지는 건너뜀
+	// 재시도를 기다리는 동안에는 저장소의 선점 조건(같은 키의 앞선 메시지가 모두 발행됨)이 순서를 보장
+	failed := make(map[string]bool)
+	claimed := 0
+	for _, msg := range msgs {
+		if ctx.Err() != nil {
+			break
+		}
+		key := msg.Topic + "#" + msg.Key
+		if msg.Key != "" && failed[key] {
+			continue
+		}
+		ok, retrying := r.process(ctx, msg)
+		if ok {
+			claimed++
+		}
+		if retrying {
+			failed[key] = true
+		}
+	}
+	return claimed, nil
+}
+
+// process - 메시지를 선점했는지, 선점한 메시지의 발행에 실패해 재시도를 예약했는지 반환
+func (r *Relay) process(ctx context.Context, msg *repository.OutboxMessage) (claimed, retrying bool) {
+	claimed, err := r.store.ClaimOutbox(ctx, msg, time.Now().Add(r.cfg.ClaimTimeout))
+	if err != nil {
+		r.logger.Error("Failed to claim outbox message",
+			zap.String("message_id", msg.MessageID),
+			zap.Error(err))
+		return false, false
+	}
+	if !claimed {
+		return false, false
+	}
+
+	publisher, ok := r.publishers[msg.Topic]
+	if !ok {
+		r.retry(ctx, msg, "no publisher registered for topic "+msg.Topic)
+		return true, true
+	}
+
+	// CloudEvents 봉투 도입 전에 기록된 메시지는 스키마 버전 1로 감싸서 발행
+	payload, err := events.WrapLegacy(msg.EventType, msg.MessageID, msg.CreatedAt, msg.Payload)
+	if err != nil {
+		r.retry(ctx, msg, err.Error())
+		return true, true
+	}
+
+	if err := publisher.PublishMessage(ctx, msg.Key, payload); err != nil {
+		r.retry(ctx, msg, err.Error())
+		return true, true
+	}
+
+	if err := r.store.MarkOutboxSent(ctx, msg, time.Now()); err != nil {
+		// 발행은 되었으므로 선점 만료 후 재발행될 수 있음 (at-least-once)
+		r.logger.Error("Failed to mark outbox message sent",
+			zap.String("message_id", msg.MessageID),
+			zap.Error(err))
+		return true, false
+	}
+
+	r.logger.Debug("Outbox message relayed",
+		zap.String("message_id", msg.MessageID),
+		zap.String("topic", msg.Topic),
+		zap.String("event_type", msg.EventType))
+	return true, false
+}
+
+func (r *Relay) retry(ctx context.Context, msg *repository.OutboxMessage, reason string) {
+	attempts := msg.Attempts + 1
+	next := time.Now().Add(r.backoff(attempts))
+
+	r.logger.Warn("Outbox publish failed, scheduling retry",
+		zap.String("message_id", msg.MessageID),
+		zap.String("topic", msg.Topic),
+		zap.Int("attempts", attempts),
+		zap.Time("next_attempt_at", next),
+		zap.String("error", reason))
+
+	if err := r.store.ScheduleOutboxRetry(ctx, msg.MessageID, attempts, next, reason); err != nil {
+		r.logger.Error("Failed to schedule outbox retry",
+			zap.String("message_id", msg.MessageID),
+			zap.Error(err))
+	}
+}
+
+// backoff - 시도 횟수에 따른 지수 백오프 (MaxBackoff 상한)
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+	return d
+}
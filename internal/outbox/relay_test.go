@@ -0,0 +1,159 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// flakyPublisher - failOnce에 있는 이벤트는 처음 한 번 발행에 실패
+type flakyPublisher struct {
+	mu        sync.Mutex
+	failOnce  map[string]bool
+	published []string
+}
+
+func (p *flakyPublisher) PublishMessage(ctx context.Context, key string, payload []byte) error {
+	var ce events.CloudEvent
+	if err := json.Unmarshal(payload, &ce); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failOnce[ce.ID] {
+		delete(p.failOnce, ce.ID)
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, ce.ID)
+	return nil
+}
+
+func (p *flakyPublisher) HealthCheck() error { return nil }
+func (p *flakyPublisher) Close() error       { return nil }
+
+func (p *flakyPublisher) Published() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.published...)
+}
+
+func enqueue(t *testing.T, store *repository.MemoryOrderRepository, orderID int, eventID string) {
+	t.Helper()
+	event := events.OrderStatusChangedEvent{EventID: eventID, OrderID: orderID, Timestamp: time.Now()}
+	msg, err := repository.NewOutboxMessage(events.TopicOrderEvents, events.EventTypeOrderStatusChanged, strconv.Itoa(orderID), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EnqueueOutbox(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRelayKeepsOrderPerKeyWhileRetrying(t *testing.T) {
+	store := repository.NewMemoryOrderRepository()
+	publisher := &flakyPublisher{failOnce: map[string]bool{"1-a": true}}
+	relay := NewRelay(store, Config{BaseBackoff: 50 * time.Millisecond}, zap.NewNop())
+	relay.Register(events.TopicOrderEvents, publisher)
+
+	enqueue(t, store, 1, "1-a")
+	enqueue(t, store, 2, "2-a")
+	enqueue(t, store, 1, "1-b")
+	enqueue(t, store, 1, "1-c")
+	ctx := context.Background()
+
+	// 1-a 발행 실패 → 같은 주문의 1-b, 1-c는 건너뛰고 다른 주문은 계속 발행
+	if _, err := relay.processBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := publisher.Published(), []string{"2-a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after failure published %v, want %v", got, want)
+	}
+
+	// 1-a가 재시도를 기다리는 동안 1-b, 1-c는 조회되지 않음
+	if _, err := relay.processBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := publisher.Published(), []string{"2-a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("during backoff published %v, want %v", got, want)
+	}
+
+	// 1-a는 재시도 시각 때문에 1-b, 1-c 뒤에 조회되므로 1-a가 발행된 다음 폴링에서 1-b, 1-c가 나감
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err := relay.processBatch(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := publisher.Published(), []string{"2-a", "1-a", "1-b", "1-c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after retry published %v, want %v", got, want)
+	}
+}
+
+// countingStore - ListPendingOutbox 호출 횟수를 셈
+type countingStore struct {
+	*repository.MemoryOrderRepository
+	lists int
+}
+
+func (s *countingStore) ListPendingOutbox(ctx context.Context, now time.Time, limit int32) ([]*repository.OutboxMessage, error) {
+	s.lists++
+	return s.MemoryOrderRepository.ListPendingOutbox(ctx, now, limit)
+}
+
+func TestRelayBlockedKeyDoesNotStallOtherKeys(t *testing.T) {
+	store := &countingStore{MemoryOrderRepository: repository.NewMemoryOrderRepository()}
+	publisher := &flakyPublisher{failOnce: map[string]bool{"1-a": true}}
+	relay := NewRelay(store, Config{BatchSize: 2, BaseBackoff: time.Hour}, zap.NewNop())
+	relay.Register(events.TopicOrderEvents, publisher)
+
+	// 배치 크기보다 많은 주문 1의 메시지 뒤에 주문 2의 메시지
+	for _, id := range []string{"1-a", "1-b", "1-c", "1-d", "1-e"} {
+		enqueue(t, store.MemoryOrderRepository, 1, id)
+	}
+	enqueue(t, store.MemoryOrderRepository, 2, "2-a")
+	ctx := context.Background()
+
+	// 1-a 발행 실패 → 선점한 메시지가 배치보다 적으므로 다음 폴링까지 기다림
+	relay.drain(ctx)
+	if store.lists != 1 {
+		t.Errorf("first drain listed %d batches, want 1", store.lists)
+	}
+
+	// 1-a가 한 시간 뒤 재시도를 기다리는 동안에도 주문 2는 발행되고, 빈 배치를 반복해서 조회하지 않음
+	for i := 0; i < 3; i++ {
+		relay.drain(ctx)
+	}
+	if got, want := publisher.Published(), []string{"2-a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	if store.lists != 4 {
+		t.Errorf("listed %d batches in 4 drains, want 4", store.lists)
+	}
+}
+
+// downPublisher - 브로커에 연결할 수 없는 발행기
+type downPublisher struct{ flakyPublisher }
+
+func (p *downPublisher) HealthCheck() error { return errors.New("no broker reachable") }
+
+func TestRelayHealthCheckCoversEveryTopic(t *testing.T) {
+	relay := NewRelay(repository.NewMemoryOrderRepository(), Config{}, zap.NewNop())
+	relay.Register(events.TopicOrderEvents, &flakyPublisher{})
+	if err := relay.HealthCheck(); err != nil {
+		t.Fatalf("health = %v, want nil", err)
+	}
+
+	relay.Register(events.TopicCompensationEvents, &downPublisher{})
+	if err := relay.HealthCheck(); err == nil {
+		t.Error("health = nil with compensation publisher down")
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// GSI 정렬키로 쓰기 위해 나노초 자릿수를 고정한 포맷
+const sortableTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+func sortableTime(t time.Time) string {
+	return t.UTC().Format(sortableTimeLayout)
+}
+
+func mustMarshal(v interface{}) types.AttributeValue {
+	av, err := attributevalue.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("attributevalue marshal: %v", err))
+	}
+	return av
+}
+
+func isConditionalCheckFailed(err error) bool {
+	var ccf *types.ConditionalCheckFailedException
+	return errors.As(err, &ccf)
+}
+
+// conditionFailedAt - 트랜잭션이 index 번째 항목의 조건 실패로 취소되었는지 확인
+func conditionFailedAt(err error, index int) bool {
+	var tce *types.TransactionCanceledException
+	if !errors.As(err, &tce) || index >= len(tce.CancellationReasons) {
+		return false
+	}
+	return aws.ToString(tce.CancellationReasons[index].Code) == "ConditionalCheckFailed"
+}
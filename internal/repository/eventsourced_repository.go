@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+const (
+	eventSKPrefix    = "EVENT#"
+	snapshotSKPrefix = "SNAPSHOT#"
+
+	// eventSourcedAttr - 이벤트 스트림이 있는 주문의 METADATA에만 붙는 속성
+	eventSourcedAttr = "EventSourced"
+)
+
+// EventSourcedOrderRepository - 주문 변경을 ORDER#<id> 파티션에 도메인 이벤트로 쌓는 저장소
+//   - EVENT#<순번>: 주문 이벤트 (순번 = 이벤트 적용 후 Version)
+//   - SNAPSHOT#<순번>: SnapshotInterval 이벤트마다 저장하는 주문 전체 상태
+//   - METADATA: 같은 트랜잭션으로 갱신하는 프로젝션 (GetOrder, GSI1 목록 조회는 그대로 사용)
+//
+// 조회, 아웃박스, 사가, 멱등성, 변경 이력은 OrderRepository를 그대로 사용
+type EventSourcedOrderRepository struct {
+	*OrderRepository
+	snapshotInterval int
+}
+
+func NewEventSourcedOrderRepository(base *OrderRepository, snapshotInterval int) *EventSourcedOrderRepository {
+	if snapshotInterval < 1 {
+		snapshotInterval = 1
+	}
+	return &EventSourcedOrderRepository{
+		OrderRepository:  base,
+		snapshotInterval: snapshotInterval,
+	}
+}
+
+func eventSK(sequence int) string {
+	return fmt.Sprintf("%s%010d", eventSKPrefix, sequence)
+}
+
+func snapshotSK(version int) string {
+	return fmt.Sprintf("%s%010d", snapshotSKPrefix, version)
+}
+
+// CreateOrder - ORDER_CREATED 이벤트와 METADATA 프로젝션을 멱등성 레코드, 변경 이력, 사가, 아웃박스와 함께 저장
+func (r *EventSourcedOrderRepository) CreateOrder(ctx context.Context, order *domain.Order, idem *IdempotencyRecord, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	av, err := projectionItem(order)
+	if err != nil {
+		return err
+	}
+	items := []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName:           aws.String(r.tableName),
+			Item:                av,
+			ConditionExpression: aws.String("attribute_not_exists(PK)"),
+		}},
+	}
+	idemIndex := -1
+	if idem != nil {
+		put, err := r.idempotencyPut(idem, time.Now())
+		if err != nil {
+			return err
+		}
+		idemIndex = len(items)
+		items = append(items, types.TransactWriteItem{Put: put})
+	}
+
+	event, err := domain.NewOrderCreatedEvent(order)
+	if err != nil {
+		return err
+	}
+	if items, err = r.appendEvent(items, event, nil, order); err != nil {
+		return err
+	}
+	if items, err = r.appendHistory(items, history); err != nil {
+		return err
+	}
+	items, sagaIndex, err := r.appendSaga(items, saga)
+	if err != nil {
+		return err
+	}
+	if items, err = r.appendOutbox(items, outbox); err != nil {
+		return err
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		if conditionFailedAt(err, 0) {
+			return ErrOrderAlreadyExists
+		}
+		if idemIndex >= 0 && conditionFailedAt(err, idemIndex) {
+			return ErrIdempotencyKeyExists
+		}
+		if sagaIndex >= 0 && conditionFailedAt(err, sagaIndex) {
+			return saga.conflict()
+		}
+		return fmt.Errorf("failed to write order transaction: %w", err)
+	}
+	saga.done()
+	return nil
+}
+
+// UpdateOrderStatus - 상태 변경 이벤트(확정/취소/그 밖의 상태)를 추가하고 METADATA의 상태와 Version 갱신
+func (r *EventSourcedOrderRepository) UpdateOrderStatus(ctx context.Context, id int, createdAt time.Time, from, to domain.OrderStatus, updatedAt time.Time, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	current, sourced, err := r.currentOrder(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrOrderNotFound) {
+			return ErrStatusConflict
+		}
+		return err
+	}
+	if current.Status != from {
+		return ErrStatusConflict
+	}
+
+	update := &types.Update{
+		TableName:           aws.String(r.tableName),
+		Key:                 orderKey(id),
+		UpdateExpression:    aws.String("SET #status = :to, UpdatedAt = :updated_at, GSI2PK = :gsi2pk, GSI2SK = :gsi2sk, " + eventSourcedAttr + " = :true ADD Version :one"),
+		ConditionExpression: aws.String("#status = :from AND Version = :expected"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":from":       &types.AttributeValueMemberS{Value: string(from)},
+			":to":         &types.AttributeValueMemberS{Value: string(to)},
+			":updated_at": mustMarshal(updatedAt),
+			":gsi2pk":     &types.AttributeValueMemberS{Value: statusIndexPK(to, id)},
+			":gsi2sk":     &types.AttributeValueMemberS{Value: statusIndexSK(current.CreatedAt, id)},
+			":true":       &types.AttributeValueMemberBOOL{Value: true},
+			":one":        &types.AttributeValueMemberN{Value: "1"},
+			":expected":   &types.AttributeValueMemberN{Value: strconv.Itoa(current.Version)},
+		},
+	}
+	if current.Version == 0 {
+		update.ConditionExpression = aws.String("#status = :from AND attribute_not_exists(Version)")
+		delete(update.ExpressionAttributeValues, ":expected")
+	}
+
+	next := *current
+	next.Status = to
+	next.UpdatedAt = updatedAt
+	next.Version = current.Version + 1
+	event, err := domain.NewStatusChangedEvent(id, next.Version, from, to, updatedAt)
+	if err != nil {
+		return err
+	}
+
+	items := []types.TransactWriteItem{{Update: update}}
+	if items, err = r.appendEvent(items, event, r.bootstrap(current, sourced), &next); err != nil {
+		return err
+	}
+	if items, err = r.appendHistory(items, history); err != nil {
+		return err
+	}
+	items, sagaIndex, err := r.appendSaga(items, saga)
+	if err != nil {
+		return err
+	}
+	if items, err = r.appendOutbox(items, outbox); err != nil {
+		return err
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		// 프로젝션 조건 또는 같은 순번의 이벤트가 먼저 기록됨
+		if conditionFailedAt(err, 0) || conditionFailedAt(err, 1) {
+			return ErrStatusConflict
+		}
+		if sagaIndex >= 0 && conditionFailedAt(err, sagaIndex) {
+			return saga.conflict()
+		}
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+	saga.done()
+	return nil
+}
+
+// UpdateOrder - ORDER_ITEMS_CHANGED 이벤트를 추가하고 METADATA를 변경 후 주문으로 교체
+func (r *EventSourcedOrderRepository) UpdateOrder(ctx context.Context, order *domain.Order, expectedVersion int, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	conflict := &VersionConflictError{OrderID: order.OrderID, ExpectedVersion: expectedVersion}
+	current, sourced, err := r.currentOrder(ctx, order.OrderID)
+	if err != nil {
+		if errors.Is(err, ErrOrderNotFound) {
+			return conflict
+		}
+		return err
+	}
+	if current.Version != expectedVersion {
+		return conflict
+	}
+
+	order.Version = expectedVersion + 1
+	items, sagaIndex, err := r.updateItems(order, expectedVersion, r.bootstrap(current, sourced), history, saga, outbox)
+	if err != nil {
+		order.Version = expectedVersion
+		return err
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		order.Version = expectedVersion
+		if conditionFailedAt(err, 0) || conditionFailedAt(err, 1) {
+			return conflict
+		}
+		if sagaIndex >= 0 && conditionFailedAt(err, sagaIndex) {
+			return saga.conflict()
+		}
+		return fmt.Errorf("failed to save order: %w", err)
+	}
+	saga.done()
+	return nil
+}
+
+// updateItems - METADATA 교체, ORDER_ITEMS_CHANGED 이벤트, 변경 이력, 사가, 아웃박스 순서의 트랜잭션 항목과 사가의 위치
+func (r *EventSourcedOrderRepository) updateItems(order *domain.Order, expectedVersion int, bootstrap *domain.Order, history *domain.HistoryEntry, saga *SagaWrite, outbox []*OutboxMessage) ([]types.TransactWriteItem, int, error) {
+	av, err := projectionItem(order)
+	if err != nil {
+		return nil, -1, err
+	}
+	event, err := domain.NewItemsChangedEvent(order)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	items := []types.TransactWriteItem{{Put: r.versionedPut(av, expectedVersion)}}
+	if items, err = r.appendEvent(items, event, bootstrap, order); err != nil {
+		return nil, -1, err
+	}
+	if items, err = r.appendHistory(items, history); err != nil {
+		return nil, -1, err
+	}
+	items, sagaIndex, err := r.appendSaga(items, saga)
+	if err != nil {
+		return nil, -1, err
+	}
+	items, err = r.appendOutbox(items, outbox)
+	return items, sagaIndex, err
+}
+
+// bootstrap - 이벤트 저장소 도입 전에 저장된 주문이면 현재 상태를 첫 스냅샷으로 남김
+func (r *EventSourcedOrderRepository) bootstrap(current *domain.Order, sourced bool) *domain.Order {
+	if sourced {
+		return nil
+	}
+	return current
+}
+
+// appendEvent - 이벤트, (필요하면) 기존 주문의 시작 스냅샷, 주기 스냅샷을 트랜잭션에 추가
+// after는 이벤트 적용 후 주문 상태
+func (r *EventSourcedOrderRepository) appendEvent(items []types.TransactWriteItem, event domain.OrderEvent, bootstrap, after *domain.Order) ([]types.TransactWriteItem, error) {
+	av, err := attributevalue.MarshalMap(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order event: %w", err)
+	}
+	av["PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", event.OrderID)}
+	av["SK"] = &types.AttributeValueMemberS{Value: eventSK(event.Sequence)}
+	items = append(items, types.TransactWriteItem{Put: &types.Put{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+		// 같은 순번의 이벤트는 한 번만 기록
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}})
+
+	var snapshots []*domain.Order
+	if bootstrap != nil {
+		snapshots = append(snapshots, bootstrap)
+	}
+	if after.Version%r.snapshotInterval == 0 {
+		snapshots = append(snapshots, after)
+	}
+	for _, snap := range snapshots {
+		put, err := r.snapshotPut(snap)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, types.TransactWriteItem{Put: put})
+	}
+	return items, nil
+}
+
+func (r *EventSourcedOrderRepository) snapshotPut(order *domain.Order) (*types.Put, error) {
+	av, err := attributevalue.MarshalMap(order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order snapshot: %w", err)
+	}
+	// GSI1 속성은 METADATA에만 두어 사용자 목록에 스냅샷이 섞이지 않게 함
+	av["PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", order.OrderID)}
+	av["SK"] = &types.AttributeValueMemberS{Value: snapshotSK(order.Version)}
+	return &types.Put{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+	}, nil
+}
+
+// currentOrder - METADATA 프로젝션과 이벤트 스트림 존재 여부
+func (r *EventSourcedOrderRepository) currentOrder(ctx context.Context, id int) (*domain.Order, bool, error) {
+	out, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            orderKey(id),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if len(out.Item) == 0 {
+		return nil, false, ErrOrderNotFound
+	}
+
+	var order domain.Order
+	if err := unmarshalOrder(out.Item, &order); err != nil {
+		return nil, false, err
+	}
+	_, sourced := out.Item[eventSourcedAttr]
+	return &order, sourced, nil
+}
+
+// LoadOrder - 최신 스냅샷과 그 이후 이벤트를 재생해 주문 복원
+func (r *EventSourcedOrderRepository) LoadOrder(ctx context.Context, id int) (*domain.Order, error) {
+	pk := &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", id)}
+
+	out, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     pk,
+			":prefix": &types.AttributeValueMemberS{Value: snapshotSKPrefix},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(1),
+		ConsistentRead:   aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order snapshot: %w", err)
+	}
+	var snapshot *domain.Order
+	from := 1
+	if len(out.Items) > 0 {
+		snapshot = &domain.Order{}
+		if err := unmarshalOrder(out.Items[0], snapshot); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal order snapshot: %w", err)
+		}
+		from = snapshot.Version + 1
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND SK BETWEEN :from AND :to"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":   pk,
+			":from": &types.AttributeValueMemberS{Value: eventSK(from)},
+			":to":   &types.AttributeValueMemberS{Value: eventSKPrefix + "9999999999"},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+	var events []domain.OrderEvent
+	for {
+		out, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query order events: %w", err)
+		}
+		for _, item := range out.Items {
+			var event domain.OrderEvent
+			if err := attributevalue.UnmarshalMap(item, &event); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal order event: %w", err)
+			}
+			events = append(events, event)
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+
+	if snapshot == nil && len(events) == 0 {
+		return nil, ErrOrderNotFound
+	}
+	return domain.ReplayOrder(snapshot, events)
+}
+
+// RebuildProjection - 이벤트를 재생한 결과로 METADATA(GSI1 포함)를 다시 씀
+// 프로젝션이 더 최신이면 덮어쓰지 않음
+func (r *EventSourcedOrderRepository) RebuildProjection(ctx context.Context, id int) (*domain.Order, error) {
+	order, err := r.LoadOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	av, err := projectionItem(order)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(Version) OR Version <= :version"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(order.Version)},
+		},
+	})
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, &VersionConflictError{OrderID: id, ExpectedVersion: order.Version}
+		}
+		return nil, fmt.Errorf("failed to rebuild order projection: %w", err)
+	}
+	return order, nil
+}
+
+// projectionItem - 기존 METADATA 아이템과 같은 형태에 이벤트 스트림 표시를 더함
+func projectionItem(order *domain.Order) (map[string]types.AttributeValue, error) {
+	av, err := orderItem(order)
+	if err != nil {
+		return nil, err
+	}
+	av[eventSourcedAttr] = &types.AttributeValueMemberBOOL{Value: true}
+	return av, nil
+}
+
+func orderKey(id int) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", id)},
+		"SK": &types.AttributeValueMemberS{Value: "METADATA"},
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	pkgconfig "github.com/cloud-wave-best-zizon/order-service/pkg/config"
+	"go.uber.org/zap"
+)
+
+// newTestDynamoRepository - DYNAMODB_ENDPOINT(DynamoDB Local 등)에 테스트마다 새 테이블을 만듦
+// DYNAMODB_ENDPOINT가 없으면 건너뜀
+//
+//	DYNAMODB_ENDPOINT=http://localhost:8000 go test ./internal/repository/
+func newTestDynamoRepository(t *testing.T) *OrderRepository {
+	t.Helper()
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_ENDPOINT not set")
+	}
+	ctx := context.Background()
+
+	client, err := NewDynamoDBClient(&pkgconfig.Config{AWSRegion: "us-east-1", DynamoDBEndpoint: endpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := fmt.Sprintf("orders-test-%d", time.Now().UnixNano())
+	if err := EnsureSchema(ctx, client, table, zap.NewNop()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(table)})
+	})
+	return NewOrderRepository(client, table)
+}
+
+func newTestEventSourcedRepository(t *testing.T, snapshotInterval int) *EventSourcedOrderRepository {
+	t.Helper()
+	return NewEventSourcedOrderRepository(newTestDynamoRepository(t), snapshotInterval)
+}
+
+func orderJSON(t *testing.T, order *domain.Order) string {
+	t.Helper()
+	b, err := json.Marshal(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestEventSourcedReplayMatchesProjection(t *testing.T) {
+	repo := newTestEventSourcedRepository(t, 2)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// ORDER_CREATED (버전 1)
+	order := testOrder(7, "user-1", now)
+	if err := repo.CreateOrder(ctx, order, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// ORDER_ITEMS_CHANGED (버전 2, SnapshotInterval 2라 스냅샷 저장)
+	order.Items[0].CancelledQuantity = 1
+	if err := order.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	order.UpdatedAt = now.Add(time.Second)
+	if err := repo.UpdateOrder(ctx, order, 1, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// ORDER_CONFIRMED (버전 3, 스냅샷 이후 이벤트)
+	if err := repo.UpdateOrderStatus(ctx, 7, now, domain.OrderStatusPending, domain.OrderStatusConfirmed, now.Add(2*time.Second), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := repo.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repo.tableName),
+		Key:       map[string]types.AttributeValue{"PK": orderKey(7)["PK"], "SK": &types.AttributeValueMemberS{Value: snapshotSK(2)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Item) == 0 {
+		t.Fatal("snapshot at version 2 not written")
+	}
+
+	projection, err := repo.GetOrder(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := repo.LoadOrder(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Version != 3 || replayed.Status != domain.OrderStatusConfirmed {
+		t.Fatalf("replayed version %d status %s, want 3 CONFIRMED", replayed.Version, replayed.Status)
+	}
+	if got, want := orderJSON(t, replayed), orderJSON(t, projection); got != want {
+		t.Errorf("replay differs from projection\n got %s\nwant %s", got, want)
+	}
+
+	// 뒤처진 프로젝션을 이벤트 재생 결과로 되돌림
+	stale := *projection
+	stale.Status = domain.OrderStatusPending
+	stale.Version = 2
+	av, err := projectionItem(&stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(repo.tableName), Item: av}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.RebuildProjection(ctx, 7); err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, err := repo.GetOrder(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := orderJSON(t, rebuilt), orderJSON(t, projection); got != want {
+		t.Errorf("rebuilt projection differs\n got %s\nwant %s", got, want)
+	}
+}
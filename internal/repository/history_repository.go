@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+const historySKPrefix = "HISTORY#"
+
+// historySK - HISTORY#<변경 시각>#<변경 후 버전>, 같은 파티션에서 시간순 정렬
+func historySK(entry *domain.HistoryEntry) string {
+	return fmt.Sprintf("%s%s#%010d", historySKPrefix, sortableTime(entry.Timestamp), entry.Version)
+}
+
+// historyPut - 주문과 같은 ORDER#<id> 파티션에 이력 아이템 추가 (덮어쓰지 않음)
+func (r *OrderRepository) historyPut(entry *domain.HistoryEntry) (*types.Put, error) {
+	av, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order history: %w", err)
+	}
+	av["PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", entry.OrderID)}
+	av["SK"] = &types.AttributeValueMemberS{Value: historySK(entry)}
+
+	return &types.Put{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}, nil
+}
+
+// ListOrderHistory - 주문의 변경 이력 전체 (오래된 순)
+func (r *OrderRepository) ListOrderHistory(ctx context.Context, orderID int) ([]*domain.HistoryEntry, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", orderID)},
+			":prefix": &types.AttributeValueMemberS{Value: historySKPrefix},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	entries := []*domain.HistoryEntry{}
+	for {
+		out, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query order history: %w", err)
+		}
+		for _, item := range out.Items {
+			var entry domain.HistoryEntry
+			if err := attributevalue.UnmarshalMap(item, &entry); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal order history: %w", err)
+			}
+			entries = append(entries, &entry)
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return entries, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+var (
+	ErrIdempotencyRecordNotFound = errors.New("idempotency record not found")
+	ErrIdempotencyKeyExists      = errors.New("idempotency key already exists")
+)
+
+// IdempotencyRecord - (user_id, idempotency_key) 별 최초 주문 생성 결과
+type IdempotencyRecord struct {
+	UserID         string                     `json:"user_id"`
+	IdempotencyKey string                     `json:"idempotency_key"`
+	RequestHash    string                     `json:"request_hash"`
+	OrderID        int                        `json:"order_id"`
+	Response       domain.CreateOrderResponse `json:"response"`
+	CreatedAt      time.Time                  `json:"created_at"`
+	ExpiresAt      int64                      `json:"expires_at"` // DynamoDB TTL (epoch seconds)
+}
+
+func (rec *IdempotencyRecord) Expired(now time.Time) bool {
+	return rec.ExpiresAt <= now.Unix()
+}
+
+func idempotencyKey(userID, key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("IDEMPOTENCY#%s#%s", userID, key)},
+		"SK": &types.AttributeValueMemberS{Value: "METADATA"},
+	}
+}
+
+// idempotencyPut - 레코드가 없거나 만료된 경우에만 기록 (TTL 삭제는 지연될 수 있음)
+func (r *OrderRepository) idempotencyPut(rec *IdempotencyRecord, now time.Time) (*types.Put, error) {
+	av, err := attributevalue.MarshalMap(rec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+	for k, v := range idempotencyKey(rec.UserID, rec.IdempotencyKey) {
+		av[k] = v
+	}
+
+	return &types.Put{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(PK) OR ExpiresAt <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	}, nil
+}
+
+// GetIdempotencyRecord - 만료되지 않은 멱등성 레코드 조회
+func (r *OrderRepository) GetIdempotencyRecord(ctx context.Context, userID, key string) (*IdempotencyRecord, error) {
+	out, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            idempotencyKey(userID, key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+	if len(out.Item) == 0 {
+		return nil, ErrIdempotencyRecordNotFound
+	}
+
+	var rec IdempotencyRecord
+	if err := attributevalue.UnmarshalMap(out.Item, &rec); err != nil {
+		return nil, err
+	}
+	if rec.Expired(time.Now()) {
+		return nil, ErrIdempotencyRecordNotFound
+	}
+	return &rec, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// 리스 아이템 - 여러 인스턴스 중 하나만 실행해야 하는 작업의 리더 선출
+// 만료 비교는 각 인스턴스의 시계를 쓰므로 리스 기간은 인스턴스 간 시계 오차보다 충분히 길어야 함
+func leaseKey(name string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("LEASE#%s", name)},
+		"SK": &types.AttributeValueMemberS{Value: "METADATA"},
+	}
+}
+
+// AcquireLease - 리스가 없거나 만료되었거나 이미 holder가 가진 경우 ttl만큼 (재)획득
+// 다른 인스턴스가 유효한 리스를 가지고 있으면 false
+func (r *OrderRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	item := leaseKey(name)
+	item["Holder"] = &types.AttributeValueMemberS{Value: holder}
+	item["LeaseUntil"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(ttl).UnixMilli(), 10)}
+
+	_, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK) OR LeaseUntil < :now OR Holder = :holder"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":    &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+			":holder": &types.AttributeValueMemberS{Value: holder},
+		},
+	})
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// ReleaseLease - holder가 가진 리스를 반납 (다른 인스턴스가 가져간 리스는 그대로 둠)
+func (r *OrderRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 leaseKey(name),
+		ConditionExpression: aws.String("Holder = :holder"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":holder": &types.AttributeValueMemberS{Value: holder},
+		},
+	})
+	if err != nil && !isConditionalCheckFailed(err) {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,476 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+// MemoryOrderRepository - 외부 의존성 없이 실행/테스트할 때 쓰는 OrderRepository 구현
+// 조건부 쓰기, not-found 에러, GSI1 정렬 순서를 DynamoDB 구현과 동일하게 맞춤
+type MemoryOrderRepository struct {
+	mu          sync.RWMutex
+	orders      map[int]*domain.Order
+	idempotency map[string]*IdempotencyRecord
+	outbox      map[string]*OutboxMessage
+	outboxSeq   map[string]int64 // 기록 순서 (같은 키의 메시지 발행 순서 보장용)
+	nextSeq     int64
+	sagas       map[int]*domain.Saga
+	history     map[int][]*domain.HistoryEntry
+	leases      map[string]memoryLease
+}
+
+type memoryLease struct {
+	holder string
+	until  time.Time
+}
+
+func NewMemoryOrderRepository() *MemoryOrderRepository {
+	return &MemoryOrderRepository{
+		orders:      make(map[int]*domain.Order),
+		idempotency: make(map[string]*IdempotencyRecord),
+		outbox:      make(map[string]*OutboxMessage),
+		outboxSeq:   make(map[string]int64),
+		sagas:       make(map[int]*domain.Saga),
+		history:     make(map[int][]*domain.HistoryEntry),
+		leases:      make(map[string]memoryLease),
+	}
+}
+
+// clone - 저장된 값과 호출자가 가진 값이 메모리를 공유하지 않도록 깊은 복사
+func clone[T any](v *T) *T {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("memory repository clone: %v", err))
+	}
+	var out T
+	if err := json.Unmarshal(b, &out); err != nil {
+		panic(fmt.Sprintf("memory repository clone: %v", err))
+	}
+	return &out
+}
+
+func idempotencyMapKey(userID, key string) string {
+	return userID + "#" + key
+}
+
+func (r *MemoryOrderRepository) CreateOrder(ctx context.Context, order *domain.Order, idem *IdempotencyRecord, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.orders[order.OrderID]; ok {
+		return ErrOrderAlreadyExists
+	}
+	if idem != nil {
+		if rec, ok := r.idempotency[idempotencyMapKey(idem.UserID, idem.IdempotencyKey)]; ok && !rec.Expired(time.Now()) {
+			return ErrIdempotencyKeyExists
+		}
+	}
+	if err := r.checkSaga(saga); err != nil {
+		return err
+	}
+	for _, msg := range outbox {
+		if _, ok := r.outbox[msg.MessageID]; ok {
+			return fmt.Errorf("failed to write order transaction: outbox message %s already exists", msg.MessageID)
+		}
+	}
+
+	r.orders[order.OrderID] = clone(order)
+	if idem != nil {
+		r.idempotency[idempotencyMapKey(idem.UserID, idem.IdempotencyKey)] = clone(idem)
+	}
+	r.putHistory(history)
+	r.putSaga(saga)
+	r.putOutbox(outbox)
+	return nil
+}
+
+func (r *MemoryOrderRepository) GetOrder(ctx context.Context, id int) (*domain.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	return clone(order), nil
+}
+
+func (r *MemoryOrderRepository) UpdateOrderStatus(ctx context.Context, id int, createdAt time.Time, from, to domain.OrderStatus, updatedAt time.Time, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || order.Status != from {
+		return ErrStatusConflict
+	}
+	if err := r.checkSaga(saga); err != nil {
+		return err
+	}
+	order.Status = to
+	order.UpdatedAt = updatedAt
+	order.Version++
+	r.putHistory(history)
+	r.putSaga(saga)
+	r.putOutbox(outbox)
+	return nil
+}
+
+func (r *MemoryOrderRepository) UpdateOrder(ctx context.Context, order *domain.Order, expectedVersion int, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.orders[order.OrderID]
+	if !ok || stored.Version != expectedVersion {
+		return &VersionConflictError{OrderID: order.OrderID, ExpectedVersion: expectedVersion}
+	}
+	if err := r.checkSaga(saga); err != nil {
+		return err
+	}
+	order.Version = expectedVersion + 1
+	r.orders[order.OrderID] = clone(order)
+	r.putHistory(history)
+	r.putSaga(saga)
+	r.putOutbox(outbox)
+	return nil
+}
+
+// checkSaga - SagaWrite의 조건 확인 (w가 nil이면 통과)
+func (r *MemoryOrderRepository) checkSaga(w *SagaWrite) error {
+	if w == nil {
+		return nil
+	}
+	stored, ok := r.sagas[w.Saga.OrderID]
+	if w.Create && ok || !w.Create && (!ok || stored.Version != w.ExpectedVersion) {
+		return w.conflict()
+	}
+	return nil
+}
+
+func (r *MemoryOrderRepository) putSaga(w *SagaWrite) {
+	if w != nil {
+		w.done()
+		r.sagas[w.Saga.OrderID] = clone(w.Saga)
+	}
+}
+
+func (r *MemoryOrderRepository) putHistory(entry *domain.HistoryEntry) {
+	if entry != nil {
+		r.history[entry.OrderID] = append(r.history[entry.OrderID], clone(entry))
+	}
+}
+
+// ListOrderHistory - DynamoDB 구현의 SK(HISTORY#<시각>#<버전>) 오름차순과 같은 순서로 반환
+func (r *MemoryOrderRepository) ListOrderHistory(ctx context.Context, orderID int) ([]*domain.HistoryEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]*domain.HistoryEntry, 0, len(r.history[orderID]))
+	for _, entry := range r.history[orderID] {
+		entries = append(entries, clone(entry))
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return historySK(entries[i]) < historySK(entries[j]) })
+	return entries, nil
+}
+
+// GetOrdersByUser - GSI1 (GSI1PK=USER#, GSI1SK=ORDER#<created>) 내림차순과 같은 순서로 반환
+func (r *MemoryOrderRepository) GetOrdersByUser(ctx context.Context, q UserOrdersQuery) (*OrderPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type entry struct {
+		sk    string
+		pk    string
+		order *domain.Order
+	}
+	var entries []entry
+	for _, order := range r.orders {
+		if order.UserID != q.UserID {
+			continue
+		}
+		if !q.matches(order) {
+			continue
+		}
+		entries = append(entries, entry{sk: userOrderSK(order.CreatedAt), pk: fmt.Sprintf("ORDER#%d", order.OrderID), order: order})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].sk != entries[j].sk {
+			return entries[i].sk > entries[j].sk
+		}
+		return entries[i].pk > entries[j].pk
+	})
+
+	start := 0
+	if len(q.StartKey) > 0 {
+		start = len(entries)
+		for i, e := range entries {
+			if e.sk == q.StartKey["GSI1SK"] && e.pk == q.StartKey["PK"] {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	// DynamoDB 구현과 같이 조건에 맞는 주문으로 Limit개를 채움
+	page := &OrderPage{Orders: []*domain.Order{}}
+	end := start + int(q.Limit)
+	if q.Limit <= 0 || end > len(entries) {
+		end = len(entries)
+	}
+	for _, e := range entries[start:end] {
+		page.Orders = append(page.Orders, clone(e.order))
+	}
+	if end < len(entries) {
+		last := entries[end-1]
+		page.NextKey = map[string]string{
+			"PK":     last.pk,
+			"SK":     "METADATA",
+			"GSI1PK": fmt.Sprintf("USER#%s", q.UserID),
+			"GSI1SK": last.sk,
+		}
+	}
+	return page, nil
+}
+
+// ListOrdersByStatus - DynamoDB 구현(GSI2 샤드 병합)과 같은 범위/순서/NextKey로 반환
+func (r *MemoryOrderRepository) ListOrdersByStatus(ctx context.Context, q StatusOrdersQuery) (*OrderPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	from, to := statusIndexRange(q)
+	last := q.StartKey["GSI2SK"]
+	var orders []*domain.Order
+	for _, order := range r.orders {
+		if q.Status != "" && order.Status != q.Status {
+			continue
+		}
+		sk := statusIndexSK(order.CreatedAt, order.OrderID)
+		if sk < from || sk > to || sk == last {
+			continue
+		}
+		orders = append(orders, clone(order))
+	}
+	return mergeStatusPage(orders, q), nil
+}
+
+func (r *MemoryOrderRepository) GetIdempotencyRecord(ctx context.Context, userID, key string) (*IdempotencyRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rec, ok := r.idempotency[idempotencyMapKey(userID, key)]
+	if !ok || rec.Expired(time.Now()) {
+		return nil, ErrIdempotencyRecordNotFound
+	}
+	return clone(rec), nil
+}
+
+// putOutbox - 호출자가 쓰기 잠금을 잡고 있어야 함
+func (r *MemoryOrderRepository) putOutbox(msgs []*OutboxMessage) {
+	for _, msg := range msgs {
+		msg.Status = OutboxStatusPending
+		if msg.NextAttemptAt.IsZero() {
+			msg.NextAttemptAt = msg.CreatedAt
+		}
+		r.outbox[msg.MessageID] = clone(msg)
+		r.nextSeq++
+		r.outboxSeq[msg.MessageID] = r.nextSeq
+	}
+}
+
+func (r *MemoryOrderRepository) EnqueueOutbox(ctx context.Context, msgs ...*OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.putOutbox(msgs)
+	return nil
+}
+
+func (r *MemoryOrderRepository) ListPendingOutbox(ctx context.Context, now time.Time, limit int32) ([]*OutboxMessage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var msgs []*OutboxMessage
+	for _, msg := range r.outbox {
+		if msg.Status == OutboxStatusPending && sortableTime(msg.NextAttemptAt) <= sortableTime(now) &&
+			!r.outboxBlocked(msg, now) {
+			msgs = append(msgs, clone(msg))
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		a, b := sortableTime(msgs[i].NextAttemptAt), sortableTime(msgs[j].NextAttemptAt)
+		if a != b {
+			return a < b
+		}
+		return r.outboxSeq[msgs[i].MessageID] < r.outboxSeq[msgs[j].MessageID]
+	})
+	if len(msgs) > int(limit) {
+		msgs = msgs[:limit]
+	}
+	return msgs, nil
+}
+
+// outboxBlocked - 같은 토픽/키의 앞선 메시지가 재시도를 기다리거나 발행 중이라 선점할 수 없는 메시지
+// (DynamoDB의 키 잠금, PostgreSQL의 seq 조건과 같음 - 호출자가 잠금을 잡고 있어야 함)
+func (r *MemoryOrderRepository) outboxBlocked(msg *OutboxMessage, now time.Time) bool {
+	if msg.Key == "" {
+		return false
+	}
+	for id, other := range r.outbox {
+		if other.Status == OutboxStatusPending && other.Topic == msg.Topic && other.Key == msg.Key &&
+			r.outboxSeq[id] < r.outboxSeq[msg.MessageID] && sortableTime(other.NextAttemptAt) > sortableTime(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryOrderRepository) ClaimOutbox(ctx context.Context, msg *OutboxMessage, until time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.outbox[msg.MessageID]
+	if !ok || stored.Status != OutboxStatusPending ||
+		sortableTime(stored.NextAttemptAt) != sortableTime(msg.NextAttemptAt) {
+		return false, nil
+	}
+	// 같은 토픽/키의 앞선 메시지가 아직 발행되지 않았으면 선점하지 않음 (재시도 대기 중인 메시지 포함)
+	if stored.Key != "" {
+		for id, other := range r.outbox {
+			if other.Status == OutboxStatusPending && other.Topic == stored.Topic && other.Key == stored.Key &&
+				r.outboxSeq[id] < r.outboxSeq[stored.MessageID] {
+				return false, nil
+			}
+		}
+	}
+	stored.NextAttemptAt = until
+	msg.NextAttemptAt = until
+	return true, nil
+}
+
+func (r *MemoryOrderRepository) MarkOutboxSent(ctx context.Context, msg *OutboxMessage, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 발행 완료된 메시지는 보관할 필요가 없으므로 바로 삭제 (DynamoDB에서는 TTL로 정리)
+	delete(r.outbox, msg.MessageID)
+	delete(r.outboxSeq, msg.MessageID)
+	return nil
+}
+
+func (r *MemoryOrderRepository) ScheduleOutboxRetry(ctx context.Context, messageID string, attempts int, next time.Time, lastErr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.outbox[messageID]
+	if !ok || stored.Status != OutboxStatusPending {
+		return fmt.Errorf("failed to schedule outbox retry: message %s is not pending", messageID)
+	}
+	stored.Attempts = attempts
+	stored.NextAttemptAt = next
+	stored.LastError = lastErr
+	return nil
+}
+
+func (r *MemoryOrderRepository) CreateSaga(ctx context.Context, saga *domain.Saga) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sagas[saga.OrderID]; ok {
+		return ErrSagaExists
+	}
+	r.sagas[saga.OrderID] = clone(saga)
+	return nil
+}
+
+func (r *MemoryOrderRepository) GetSaga(ctx context.Context, orderID int) (*domain.Saga, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	saga, ok := r.sagas[orderID]
+	if !ok {
+		return nil, ErrSagaNotFound
+	}
+	return clone(saga), nil
+}
+
+func (r *MemoryOrderRepository) SaveSaga(ctx context.Context, saga *domain.Saga, expectedVersion int, outbox ...*OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.sagas[saga.OrderID]
+	if !ok || stored.Version != expectedVersion {
+		return ErrSagaVersionConflict
+	}
+	saga.Version = expectedVersion + 1
+	r.sagas[saga.OrderID] = clone(saga)
+	r.putOutbox(outbox)
+	return nil
+}
+
+func (r *MemoryOrderRepository) ListDueSagas(ctx context.Context, now time.Time, limit int32) ([]*domain.Saga, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type entry struct {
+		due  string
+		saga *domain.Saga
+	}
+	var entries []entry
+	for _, saga := range r.sagas {
+		var due time.Time
+		switch {
+		case saga.Status == domain.SagaStatusRunning:
+			due = saga.Deadline
+		case !saga.OrderUpdated:
+			due = saga.UpdatedAt
+		default:
+			continue
+		}
+		if sortableTime(due) <= sortableTime(now) {
+			entries = append(entries, entry{due: sortableTime(due), saga: saga})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].due != entries[j].due {
+			return entries[i].due < entries[j].due
+		}
+		return strconv.Itoa(entries[i].saga.OrderID) < strconv.Itoa(entries[j].saga.OrderID)
+	})
+
+	sagas := make([]*domain.Saga, 0, len(entries))
+	for i, e := range entries {
+		if i == int(limit) {
+			break
+		}
+		sagas = append(sagas, clone(e.saga))
+	}
+	return sagas, nil
+}
+
+func (r *MemoryOrderRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if lease, ok := r.leases[name]; ok && lease.holder != holder && !lease.until.Before(now) {
+		return false, nil
+	}
+	r.leases[name] = memoryLease{holder: holder, until: now.Add(ttl)}
+	return true, nil
+}
+
+func (r *MemoryOrderRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lease, ok := r.leases[name]; ok && lease.holder == holder {
+		delete(r.leases, name)
+	}
+	return nil
+}
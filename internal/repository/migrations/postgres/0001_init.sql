@@ -0,0 +1,76 @@
+-- 주문 (금액은 통화 최소 단위 정수)
+CREATE TABLE orders (
+    order_id        BIGINT      PRIMARY KEY,
+    user_id         TEXT        NOT NULL,
+    status          TEXT        NOT NULL,
+    total_amount    BIGINT      NOT NULL,
+    currency        CHAR(3)     NOT NULL,
+    idempotency_key TEXT        NOT NULL DEFAULT '',
+    catalog_version TEXT        NOT NULL DEFAULT '',
+    line_history    JSONB       NOT NULL DEFAULT '[]',
+    version         INTEGER     NOT NULL DEFAULT 1,
+    created_at      TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL
+);
+
+-- 사용자 주문 목록 (최신순 키셋 페이지네이션)
+CREATE INDEX orders_user_created_idx ON orders (user_id, created_at DESC, order_id DESC);
+
+CREATE TABLE order_items (
+    order_id           BIGINT  NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    line_id            INTEGER NOT NULL,
+    product_id         TEXT    NOT NULL,
+    product_name       TEXT    NOT NULL DEFAULT '',
+    quantity           INTEGER NOT NULL,
+    price_amount       BIGINT  NOT NULL,
+    price_currency     CHAR(3) NOT NULL,
+    status             TEXT    NOT NULL DEFAULT '',
+    cancelled_quantity INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (order_id, line_id)
+);
+
+CREATE TABLE order_history (
+    order_id    BIGINT      NOT NULL,
+    version     INTEGER     NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    entry       JSONB       NOT NULL,
+    PRIMARY KEY (order_id, recorded_at, version)
+);
+
+CREATE TABLE idempotency_records (
+    user_id         TEXT        NOT NULL,
+    idempotency_key TEXT        NOT NULL,
+    request_hash    TEXT        NOT NULL,
+    order_id        BIGINT      NOT NULL,
+    response        JSONB       NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    expires_at      BIGINT      NOT NULL, -- epoch seconds
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE TABLE outbox_messages (
+    message_id      TEXT        PRIMARY KEY,
+    topic           TEXT        NOT NULL,
+    event_type      TEXT        NOT NULL,
+    key             TEXT        NOT NULL,
+    payload         BYTEA       NOT NULL,
+    status          TEXT        NOT NULL,
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    sent_at         TIMESTAMPTZ
+);
+
+-- 릴레이는 대기 중인 메시지만 조회
+CREATE INDEX outbox_pending_idx ON outbox_messages (next_attempt_at) WHERE status = 'PENDING';
+
+CREATE TABLE sagas (
+    order_id BIGINT      PRIMARY KEY,
+    data     JSONB       NOT NULL,
+    version  INTEGER     NOT NULL,
+    -- 타임아웃 또는 주문 반영이 필요한 시각 (처리할 일이 없으면 NULL)
+    due_at   TIMESTAMPTZ
+);
+
+CREATE INDEX sagas_due_idx ON sagas (due_at) WHERE due_at IS NOT NULL;
@@ -0,0 +1,5 @@
+-- 운영용 상태/생성일 주문 조회 (ListOrdersByStatus)
+CREATE INDEX orders_status_created_idx ON orders (status, created_at, order_id);
+
+-- 상태 없이 생성일 범위로만 조회할 때
+CREATE INDEX orders_created_idx ON orders (created_at, order_id);
@@ -0,0 +1,6 @@
+-- 여러 인스턴스 중 하나만 실행해야 하는 작업의 리더 선출 (AcquireLease)
+CREATE TABLE leases (
+    name        TEXT        PRIMARY KEY,
+    holder      TEXT        NOT NULL,
+    lease_until TIMESTAMPTZ NOT NULL
+);
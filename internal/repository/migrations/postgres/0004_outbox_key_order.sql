@@ -0,0 +1,4 @@
+-- 같은 토픽/키(주문 ID)의 메시지는 기록된 순서(seq)대로 하나씩 발행 (ClaimOutbox)
+ALTER TABLE outbox_messages ADD COLUMN seq BIGSERIAL;
+
+CREATE INDEX outbox_pending_key_idx ON outbox_messages (topic, key, seq) WHERE status = 'PENDING';
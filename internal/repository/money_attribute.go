@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+// domain.Money는 기본 인코딩으로 {Amount: N(최소 단위), Currency: S} 맵으로 저장됨
+// 금액이 float64(N)였던 기존 주문은 읽기 전에 같은 맵 형식으로 바꿔야 함
+
+// unmarshalOrder - 주문 아이템(METADATA, 스냅샷)을 기존 금액 형식까지 읽어 변환
+func unmarshalOrder(item map[string]types.AttributeValue, order *domain.Order) error {
+	if err := upgradeMoneyAttributes(item); err != nil {
+		return err
+	}
+	return attributevalue.UnmarshalMap(item, order)
+}
+
+// upgradeMoneyAttributes - 주문의 금액 속성(TotalAmount, Items[].Price)을 현재 형식으로 바꿈
+func upgradeMoneyAttributes(item map[string]types.AttributeValue) error {
+	if err := upgradeMoney(item, "TotalAmount"); err != nil {
+		return err
+	}
+	items, ok := item["Items"].(*types.AttributeValueMemberL)
+	if !ok {
+		return nil
+	}
+	for _, av := range items.Value {
+		if line, ok := av.(*types.AttributeValueMemberM); ok {
+			if err := upgradeMoney(line.Value, "Price"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// upgradeMoney - float 금액(N)은 기본 통화로 반올림하고, 통화가 없는 맵에는 기본 통화를 채움
+func upgradeMoney(attrs map[string]types.AttributeValue, name string) error {
+	switch v := attrs[name].(type) {
+	case *types.AttributeValueMemberN:
+		m, err := domain.ParseLegacyMoney(v.Value)
+		if err != nil {
+			return fmt.Errorf("failed to read legacy %s: %w", name, err)
+		}
+		attrs[name] = moneyAttribute(m)
+	case *types.AttributeValueMemberM:
+		if _, ok := v.Value["Amount"].(*types.AttributeValueMemberN); !ok {
+			return fmt.Errorf("%w: %s has no numeric Amount", domain.ErrInvalidAmount, name)
+		}
+		if c, ok := v.Value["Currency"].(*types.AttributeValueMemberS); !ok || c.Value == "" {
+			v.Value["Currency"] = &types.AttributeValueMemberS{Value: domain.DefaultCurrency()}
+		}
+	}
+	return nil
+}
+
+func moneyAttribute(m domain.Money) types.AttributeValue {
+	return &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"Amount":   &types.AttributeValueMemberN{Value: strconv.FormatInt(m.Amount, 10)},
+		"Currency": &types.AttributeValueMemberS{Value: m.Currency},
+	}}
+}
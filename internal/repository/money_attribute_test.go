@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+func TestOrderItemMoneyRoundTrip(t *testing.T) {
+	order := testOrder(1, "user-1", time.Now().UTC().Truncate(time.Millisecond))
+	order.Items[0].Price = domain.Money{Amount: 1234, Currency: "USD"}
+	order.TotalAmount = domain.Money{Amount: -5, Currency: "USD"}
+
+	item, err := orderItem(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := moneyAttribute(order.TotalAmount)
+	if got := item["TotalAmount"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("TotalAmount attribute = %#v, want %#v", got, want)
+	}
+
+	var got domain.Order
+	if err := unmarshalOrder(item, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.TotalAmount != order.TotalAmount || got.Items[0].Price != order.Items[0].Price {
+		t.Errorf("read total %+v price %+v, want %+v %+v", got.TotalAmount, got.Items[0].Price, order.TotalAmount, order.Items[0].Price)
+	}
+}
+
+func TestUnmarshalOrderLegacyMoney(t *testing.T) {
+	item, err := orderItem(testOrder(1, "user-1", time.Now().UTC()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// float64로 저장된 기존 주문
+	item["TotalAmount"] = &types.AttributeValueMemberN{Value: "5999.5"}
+	lines := item["Items"].(*types.AttributeValueMemberL).Value
+	lines[0].(*types.AttributeValueMemberM).Value["Price"] = &types.AttributeValueMemberN{Value: "1499.9999999999998"}
+	// 통화 없이 최소 단위만 있는 금액
+	lines[1].(*types.AttributeValueMemberM).Value["Price"] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"Amount": &types.AttributeValueMemberN{Value: "3000"},
+	}}
+
+	var order domain.Order
+	if err := unmarshalOrder(item, &order); err != nil {
+		t.Fatal(err)
+	}
+	currency := domain.DefaultCurrency()
+	if want := (domain.Money{Amount: 6000, Currency: currency}); order.TotalAmount != want {
+		t.Errorf("TotalAmount = %+v, want %+v", order.TotalAmount, want)
+	}
+	if want := (domain.Money{Amount: 1500, Currency: currency}); order.Items[0].Price != want {
+		t.Errorf("Items[0].Price = %+v, want %+v", order.Items[0].Price, want)
+	}
+	if want := (domain.Money{Amount: 3000, Currency: currency}); order.Items[1].Price != want {
+		t.Errorf("Items[1].Price = %+v, want %+v", order.Items[1].Price, want)
+	}
+}
+
+func TestUnmarshalOrderRejectsInvalidMoney(t *testing.T) {
+	for name, av := range map[string]types.AttributeValue{
+		"overflow":          &types.AttributeValueMemberN{Value: "1e19"},
+		"map without N":     &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"Amount": &types.AttributeValueMemberS{Value: "10"}}},
+		"non-integer minor": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"Amount": &types.AttributeValueMemberN{Value: "1.5"}, "Currency": &types.AttributeValueMemberS{Value: "KRW"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			item, err := orderItem(testOrder(1, "user-1", time.Now().UTC()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			item["TotalAmount"] = av
+			var order domain.Order
+			if err := unmarshalOrder(item, &order); err == nil {
+				t.Errorf("read %+v, want error", order.TotalAmount)
+			}
+		})
+	}
+}
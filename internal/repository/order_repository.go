@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -19,19 +23,27 @@ type OrderRepository struct {
 	tableName string
 }
 
+// NewDynamoDBClient - DYNAMODB_ENDPOINT가 있으면 DynamoDB Local 등 해당 엔드포인트로 접속
 func NewDynamoDBClient(cfg *pkgconfig.Config) (*dynamodb.Client, error) {
-	var awsCfg aws.Config
-	var err error
-
-	    awsCfg, err = config.LoadDefaultConfig(context.TODO(),
-        config.WithRegion(cfg.AWSRegion),
-	)
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(cfg.AWSRegion),
+	}
+	if cfg.DynamoDBEndpoint != "" && os.Getenv("AWS_ACCESS_KEY_ID") == "" {
+		// DynamoDB Local은 자격 증명을 검사하지 않지만 SDK 서명에는 값이 필요
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider("local", "local", "")))
+	}
 
+	awsCfg, err := config.LoadDefaultConfig(context.TODO(), opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	return dynamodb.NewFromConfig(awsCfg), nil
+	return dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		if cfg.DynamoDBEndpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.DynamoDBEndpoint)
+		}
+	}), nil
 }
 
 func NewOrderRepository(client *dynamodb.Client, tableName string) *OrderRepository {
@@ -41,32 +53,81 @@ func NewOrderRepository(client *dynamodb.Client, tableName string) *OrderReposit
 	}
 }
 
-func (r *OrderRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
-	// Order를 DynamoDB 아이템으로 변환
-	av, err := attributevalue.MarshalMap(order)
+// CreateOrder - 주문, 멱등성 레코드, 변경 이력, 사가, 아웃박스 메시지를 하나의 트랜잭션으로 저장
+// idem이 nil이 아니고 이미 유효한 레코드가 있으면 ErrIdempotencyKeyExists 반환
+func (r *OrderRepository) CreateOrder(ctx context.Context, order *domain.Order, idem *IdempotencyRecord, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	av, err := orderItem(order)
 	if err != nil {
-		return fmt.Errorf("failed to marshal order: %w", err)
+		return err
 	}
 
-	// PK, SK 추가 - OrderID는 int이므로 %d 사용
-	av["PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", order.OrderID)}
-	av["SK"] = &types.AttributeValueMemberS{Value: "METADATA"}
-	av["GSI1PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", order.UserID)}
-	av["GSI1SK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%s", order.CreatedAt.Format("2006-01-02T15:04:05Z"))}
+	items := []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName: aws.String(r.tableName),
+			Item:      av,
+			// 같은 OrderID가 이미 있으면 덮어쓰지 않고 실패
+			ConditionExpression: aws.String("attribute_not_exists(PK)"),
+		}},
+	}
+	idemIndex := -1
+	if idem != nil {
+		put, err := r.idempotencyPut(idem, time.Now())
+		if err != nil {
+			return err
+		}
+		idemIndex = len(items)
+		items = append(items, types.TransactWriteItem{Put: put})
+	}
+	if items, err = r.appendHistory(items, history); err != nil {
+		return err
+	}
+	items, sagaIndex, err := r.appendSaga(items, saga)
+	if err != nil {
+		return err
+	}
+	if items, err = r.appendOutbox(items, outbox); err != nil {
+		return err
+	}
 
 	// DynamoDB에 저장
-	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      av,
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to put item: %w", err)
+		if conditionFailedAt(err, 0) {
+			return ErrOrderAlreadyExists
+		}
+		if idemIndex >= 0 && conditionFailedAt(err, idemIndex) {
+			return ErrIdempotencyKeyExists
+		}
+		if sagaIndex >= 0 && conditionFailedAt(err, sagaIndex) {
+			return saga.conflict()
+		}
+		return fmt.Errorf("failed to write order transaction: %w", err)
 	}
+	saga.done()
 
 	return nil
 }
 
+// orderItem - Order를 키와 GSI1(사용자별), GSI2(상태별) 속성이 포함된 DynamoDB 아이템으로 변환
+func orderItem(order *domain.Order) (map[string]types.AttributeValue, error) {
+	av, err := attributevalue.MarshalMap(order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order: %w", err)
+	}
+
+	// PK, SK 추가 - OrderID는 int이므로 %d 사용
+	av["PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", order.OrderID)}
+	av["SK"] = &types.AttributeValueMemberS{Value: "METADATA"}
+	av["GSI1PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", order.UserID)}
+	av["GSI1SK"] = &types.AttributeValueMemberS{Value: userOrderSK(order.CreatedAt)}
+	av["GSI2PK"] = &types.AttributeValueMemberS{Value: statusIndexPK(order.Status, order.OrderID)}
+	av["GSI2SK"] = &types.AttributeValueMemberS{Value: statusIndexSK(order.CreatedAt, order.OrderID)}
+	return av, nil
+}
+
 func (r *OrderRepository) GetOrder(ctx context.Context, id int) (*domain.Order, error) {
 	pk := fmt.Sprintf("ORDER#%d", id)
 
@@ -86,41 +147,277 @@ func (r *OrderRepository) GetOrder(ctx context.Context, id int) (*domain.Order,
 	}
 
 	var order domain.Order
-	if err := attributevalue.UnmarshalMap(out.Item, &order); err != nil {
+	if err := unmarshalOrder(out.Item, &order); err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
 
-// GetOrdersByUser - 특정 사용자의 주문 목록 조회
-func (r *OrderRepository) GetOrdersByUser(ctx context.Context, userID string, limit int32) ([]*domain.Order, error) {
-	gsi1pk := fmt.Sprintf("USER#%s", userID)
+// UpdateOrderStatus - 현재 상태가 from일 때만 to로 변경하고, 변경 이력, 사가, 아웃박스 메시지를 같은 트랜잭션으로 기록
+// 그 사이 다른 요청이 상태를 바꿨으면 ErrStatusConflict 반환
+func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, id int, createdAt time.Time, from, to domain.OrderStatus, updatedAt time.Time, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	items := []types.TransactWriteItem{
+		{Update: &types.Update{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", id)},
+				"SK": &types.AttributeValueMemberS{Value: "METADATA"},
+			},
+			// GSI2SK가 없는 기존 주문도 상태를 바꾸는 시점에 상태 인덱스에 들어가도록 함께 기록
+			UpdateExpression:    aws.String("SET #status = :to, UpdatedAt = :updated_at, GSI2PK = :gsi2pk, GSI2SK = if_not_exists(GSI2SK, :gsi2sk) ADD Version :one"),
+			ConditionExpression: aws.String("#status = :from"),
+			ExpressionAttributeNames: map[string]string{
+				"#status": "Status",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":from":       &types.AttributeValueMemberS{Value: string(from)},
+				":to":         &types.AttributeValueMemberS{Value: string(to)},
+				":updated_at": mustMarshal(updatedAt),
+				":gsi2pk":     &types.AttributeValueMemberS{Value: statusIndexPK(to, id)},
+				":gsi2sk":     &types.AttributeValueMemberS{Value: statusIndexSK(createdAt, id)},
+				":one":        &types.AttributeValueMemberN{Value: "1"},
+			},
+		}},
+	}
+	items, err := r.appendHistory(items, history)
+	if err != nil {
+		return err
+	}
+	items, sagaIndex, err := r.appendSaga(items, saga)
+	if err != nil {
+		return err
+	}
+	if items, err = r.appendOutbox(items, outbox); err != nil {
+		return err
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		if conditionFailedAt(err, 0) {
+			return ErrStatusConflict
+		}
+		if sagaIndex >= 0 && conditionFailedAt(err, sagaIndex) {
+			return saga.conflict()
+		}
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+	saga.done()
+	return nil
+}
+
+// UpdateOrder - 저장된 Version이 expectedVersion일 때만 주문 전체를 덮어쓰고 Version을 1 올림
+// 변경 이력, 사가, 아웃박스 메시지는 같은 트랜잭션으로 기록하며, 그 사이 변경되었으면 *VersionConflictError 반환
+func (r *OrderRepository) UpdateOrder(ctx context.Context, order *domain.Order, expectedVersion int, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	order.Version = expectedVersion + 1
+	av, err := orderItem(order)
+	if err != nil {
+		order.Version = expectedVersion
+		return err
+	}
+
+	items := []types.TransactWriteItem{{Put: r.versionedPut(av, expectedVersion)}}
+	if items, err = r.appendHistory(items, history); err != nil {
+		order.Version = expectedVersion
+		return err
+	}
+	items, sagaIndex, err := r.appendSaga(items, saga)
+	if err != nil {
+		order.Version = expectedVersion
+		return err
+	}
+	if items, err = r.appendOutbox(items, outbox); err != nil {
+		order.Version = expectedVersion
+		return err
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		order.Version = expectedVersion
+		if conditionFailedAt(err, 0) {
+			return &VersionConflictError{OrderID: order.OrderID, ExpectedVersion: expectedVersion}
+		}
+		if sagaIndex >= 0 && conditionFailedAt(err, sagaIndex) {
+			return saga.conflict()
+		}
+		return fmt.Errorf("failed to save order: %w", err)
+	}
+	saga.done()
+	return nil
+}
 
-	out, err := r.client.Query(ctx, &dynamodb.QueryInput{
-		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("GSI1"),
-		KeyConditionExpression: aws.String("GSI1PK = :gsi1pk"),
+// versionedPut - 저장된 Version이 expectedVersion일 때만 성공하는 METADATA Put
+func (r *OrderRepository) versionedPut(av map[string]types.AttributeValue, expectedVersion int) *types.Put {
+	put := &types.Put{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("Version = :expected"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":gsi1pk": &types.AttributeValueMemberS{Value: gsi1pk},
+			":expected": &types.AttributeValueMemberN{Value: strconv.Itoa(expectedVersion)},
 		},
-		Limit:            aws.Int32(limit),
-		ScanIndexForward: aws.Bool(false), // 최신 주문부터
-	})
+	}
+	if expectedVersion == 0 {
+		// Version 속성이 생기기 전에 저장된 주문
+		put.ConditionExpression = aws.String("attribute_exists(PK) AND attribute_not_exists(Version)")
+		put.ExpressionAttributeValues = nil
+	}
+	return put
+}
 
+func (r *OrderRepository) appendHistory(items []types.TransactWriteItem, history *domain.HistoryEntry) ([]types.TransactWriteItem, error) {
+	if history == nil {
+		return items, nil
+	}
+	put, err := r.historyPut(history)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query orders: %w", err)
+		return nil, err
 	}
+	return append(items, types.TransactWriteItem{Put: put}), nil
+}
 
-	orders := make([]*domain.Order, 0, len(out.Items))
-	for _, item := range out.Items {
-		var order domain.Order
-		if err := attributevalue.UnmarshalMap(item, &order); err != nil {
+func (r *OrderRepository) appendOutbox(items []types.TransactWriteItem, outbox []*OutboxMessage) ([]types.TransactWriteItem, error) {
+	for _, msg := range outbox {
+		put, err := r.outboxPut(msg)
+		if err != nil {
 			return nil, err
 		}
-		orders = append(orders, &order)
+		items = append(items, types.TransactWriteItem{Put: put})
+	}
+	return items, nil
+}
+
+// UserOrdersQuery - 사용자 주문 목록 조회 조건
+type UserOrdersQuery struct {
+	UserID string
+	Limit  int32
+	// 선택 조건
+	Status        domain.OrderStatus
+	CreatedAfter  time.Time // 포함
+	CreatedBefore time.Time // 포함
+	// 이전 페이지의 NextKey
+	StartKey map[string]string
+}
+
+// matches - 상태와 정확한 생성 시각 조건 (GSI1SK는 초 단위라 키 조건만으로는 경계 초 전체가 포함됨)
+func (q UserOrdersQuery) matches(order *domain.Order) bool {
+	if q.Status != "" && order.Status != q.Status {
+		return false
+	}
+	if !q.CreatedAfter.IsZero() && order.CreatedAt.Before(q.CreatedAfter) {
+		return false
+	}
+	if !q.CreatedBefore.IsZero() && order.CreatedAt.After(q.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// OrderPage - 한 페이지의 주문과 다음 페이지 시작 키 (마지막 페이지면 nil)
+type OrderPage struct {
+	Orders  []*domain.Order
+	NextKey map[string]string
+}
+
+// GetOrdersByUser - 특정 사용자의 주문 목록 조회 (최신순, GSI1SK 범위로 생성일 필터)
+// 상태와 정확한 생성 시각은 Limit만큼 읽은 뒤 거르므로, 페이지가 Limit개가 될 때까지 이어서 조회
+// 페이지를 채운 직후 인덱스가 끝나면 NextKey가 있어도 다음 페이지는 비어 있을 수 있음
+func (r *OrderRepository) GetOrdersByUser(ctx context.Context, q UserOrdersQuery) (*OrderPage, error) {
+	keyCond := "GSI1PK = :gsi1pk"
+	values := map[string]types.AttributeValue{
+		":gsi1pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", q.UserID)},
+	}
+	switch {
+	case !q.CreatedAfter.IsZero() && !q.CreatedBefore.IsZero():
+		keyCond += " AND GSI1SK BETWEEN :from AND :to"
+		values[":from"] = &types.AttributeValueMemberS{Value: userOrderSK(q.CreatedAfter)}
+		values[":to"] = &types.AttributeValueMemberS{Value: userOrderSK(q.CreatedBefore)}
+	case !q.CreatedAfter.IsZero():
+		keyCond += " AND GSI1SK >= :from"
+		values[":from"] = &types.AttributeValueMemberS{Value: userOrderSK(q.CreatedAfter)}
+	case !q.CreatedBefore.IsZero():
+		keyCond += " AND GSI1SK <= :to"
+		values[":to"] = &types.AttributeValueMemberS{Value: userOrderSK(q.CreatedBefore)}
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String("GSI1"),
+		KeyConditionExpression:    aws.String(keyCond),
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(false), // 최신 주문부터
+	}
+	if q.Status != "" {
+		input.FilterExpression = aws.String("#status = :status")
+		input.ExpressionAttributeNames = map[string]string{"#status": "Status"}
+		values[":status"] = &types.AttributeValueMemberS{Value: string(q.Status)}
+	}
+	if len(q.StartKey) > 0 {
+		input.ExclusiveStartKey = make(map[string]types.AttributeValue, len(q.StartKey))
+		for k, v := range q.StartKey {
+			input.ExclusiveStartKey[k] = &types.AttributeValueMemberS{Value: v}
+		}
 	}
 
-	return orders, nil
+	page := &OrderPage{Orders: []*domain.Order{}}
+	for {
+		// 남은 개수만큼만 읽어 LastEvaluatedKey가 페이지의 마지막 주문 이후를 가리키지 않도록 함
+		if q.Limit > 0 {
+			input.Limit = aws.Int32(q.Limit - int32(len(page.Orders)))
+		}
+		out, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query orders: %w", err)
+		}
+
+		for _, item := range out.Items {
+			var order domain.Order
+			if err := unmarshalOrder(item, &order); err != nil {
+				return nil, err
+			}
+			if q.matches(&order) {
+				page.Orders = append(page.Orders, &order)
+			}
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return page, nil
+		}
+		if q.Limit > 0 && len(page.Orders) >= int(q.Limit) {
+			page.NextKey = make(map[string]string, len(out.LastEvaluatedKey))
+			for k, v := range out.LastEvaluatedKey {
+				if s, ok := v.(*types.AttributeValueMemberS); ok {
+					page.NextKey[k] = s.Value
+				}
+			}
+			return page, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+func userOrderSK(createdAt time.Time) string {
+	return fmt.Sprintf("ORDER#%s", createdAt.UTC().Format("2006-01-02T15:04:05Z"))
+}
+
+var (
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrOrderAlreadyExists = errors.New("order already exists")
+	ErrStatusConflict     = errors.New("order status changed concurrently")
+	ErrVersionConflict    = errors.New("order version conflict")
+)
+
+// VersionConflictError - 읽은 뒤 다른 요청이 먼저 주문을 변경함 (errors.Is(err, ErrVersionConflict))
+type VersionConflictError struct {
+	OrderID         int
+	ExpectedVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: order %d is no longer at version %d", ErrVersionConflict, e.OrderID, e.ExpectedVersion)
 }
 
-var ErrOrderNotFound = errors.New("order not found")
\ No newline at end of file
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
\ No newline at end of file
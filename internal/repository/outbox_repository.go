@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/google/uuid"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "PENDING"
+	OutboxStatusSent    OutboxStatus = "SENT"
+)
+
+const (
+	// 샤드 도입 전에 기록된 대기 메시지의 GSI1PK (발행되면 GSI에서 빠지므로 조회만 유지)
+	outboxLegacyPendingPK = "OUTBOX#PENDING"
+	// 대기 메시지가 GSI1의 한 파티션에 몰리지 않도록 키(주문 ID)로 나눔
+	outboxPendingShards = 8
+	// 발행 완료된 아웃박스 레코드는 TTL로 정리
+	outboxSentRetention = 7 * 24 * time.Hour
+)
+
+// OutboxMessage - 주문과 같은 트랜잭션으로 기록되는 발행 대기 이벤트
+type OutboxMessage struct {
+	MessageID     string       `json:"message_id"`
+	Topic         string       `json:"topic"`
+	EventType     string       `json:"event_type"`
+	Key           string       `json:"key"`
+	Payload       []byte       `json:"payload"`
+	Status        OutboxStatus `json:"status"`
+	Attempts      int          `json:"attempts"`
+	LastError     string       `json:"last_error,omitempty"`
+	NextAttemptAt time.Time    `json:"next_attempt_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// NewOutboxMessage - 이벤트를 CloudEvents 봉투(structured JSON)에 담아 발행 대기 메시지 생성
+// Kafka binary 모드 변환은 발행 시 Producer가 담당
+func NewOutboxMessage(topic, eventType, key string, event interface{}) (*OutboxMessage, error) {
+	messageID := uuid.New().String()
+	ce, err := events.NewCloudEvent(eventType, messageID, event)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(ce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	return &OutboxMessage{
+		MessageID: messageID,
+		Topic:     topic,
+		EventType: eventType,
+		Key:       key,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func outboxPK(messageID string) string {
+	return fmt.Sprintf("OUTBOX#%s", messageID)
+}
+
+// outboxPendingPK - OUTBOX#PENDING#<샤드>, 키가 주문 ID이므로 orderShard와 같은 샤드
+// 같은 키의 메시지는 한 샤드에 모여 샤드 안에서 기록 순서대로 조회됨
+func outboxPendingPK(msg *OutboxMessage) string {
+	key := msg.Key
+	if key == "" {
+		key = msg.MessageID
+	}
+	return outboxShardPK(keyShard(key, outboxPendingShards))
+}
+
+func outboxShardPK(shard int) string {
+	return fmt.Sprintf("%s#%d", outboxLegacyPendingPK, shard)
+}
+
+func (r *OrderRepository) outboxPut(msg *OutboxMessage) (*types.Put, error) {
+	msg.Status = OutboxStatusPending
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = msg.CreatedAt
+	}
+
+	av, err := attributevalue.MarshalMap(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal outbox message: %w", err)
+	}
+
+	av["PK"] = &types.AttributeValueMemberS{Value: outboxPK(msg.MessageID)}
+	av["SK"] = &types.AttributeValueMemberS{Value: "METADATA"}
+	// 대기 중인 메시지만 GSI1에 노출시켜 릴레이가 조회할 수 있도록 함
+	av["GSI1PK"] = &types.AttributeValueMemberS{Value: outboxPendingPK(msg)}
+	av["GSI1SK"] = &types.AttributeValueMemberS{Value: sortableTime(msg.NextAttemptAt)}
+
+	return &types.Put{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}, nil
+}
+
+// EnqueueOutbox - 주문 변경 없이 메시지만 발행 대기열에 기록 (이벤트 재발행 등)
+func (r *OrderRepository) EnqueueOutbox(ctx context.Context, msgs ...*OutboxMessage) error {
+	items, err := r.appendOutbox(nil, msgs)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue outbox messages: %w", err)
+	}
+	return nil
+}
+
+// ListPendingOutbox - 발행 시각이 도래한 대기 메시지를 오래된 순으로 조회
+// 모든 샤드(와 샤드 도입 전 파티션)를 병렬로 limit건씩 조회해 다음 시도 시각 순으로 병합
+// 다른 메시지가 키 잠금을 가진(앞선 메시지가 재시도를 기다리거나 발행 중인) 메시지는 선점할 수 없으므로 제외
+func (r *OrderRepository) ListPendingOutbox(ctx context.Context, now time.Time, limit int32) ([]*OutboxMessage, error) {
+	pks := make([]string, 0, outboxPendingShards+1)
+	for shard := 0; shard < outboxPendingShards; shard++ {
+		pks = append(pks, outboxShardPK(shard))
+	}
+	pks = append(pks, outboxLegacyPendingPK)
+
+	type result struct {
+		msgs []*OutboxMessage
+		err  error
+	}
+	results := make([]result, len(pks))
+	var wg sync.WaitGroup
+	for i, pk := range pks {
+		wg.Add(1)
+		go func(i int, pk string) {
+			defer wg.Done()
+			msgs, err := r.queryPendingOutbox(ctx, pk, now, limit)
+			results[i] = result{msgs: msgs, err: err}
+		}(i, pk)
+	}
+	wg.Wait()
+
+	var msgs []*OutboxMessage
+	for _, res := range results {
+		if res.err != nil {
+			return nil, res.err
+		}
+		msgs = append(msgs, res.msgs...)
+	}
+	// 같은 키의 메시지는 한 샤드의 GSI1SK 순서를 유지
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return sortableTime(msgs[i].NextAttemptAt) < sortableTime(msgs[j].NextAttemptAt)
+	})
+	if len(msgs) > int(limit) {
+		msgs = msgs[:limit]
+	}
+	return msgs, nil
+}
+
+// queryPendingOutbox - 한 파티션에서 선점할 수 있는 메시지를 최대 limit건
+// 잠긴 키의 메시지가 한 페이지를 채워도 다른 키의 메시지가 조회되도록 다음 페이지를 이어서 읽음
+func (r *OrderRepository) queryPendingOutbox(ctx context.Context, pk string, now time.Time, limit int32) ([]*OutboxMessage, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("GSI1"),
+		KeyConditionExpression: aws.String("GSI1PK = :pk AND GSI1SK <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":  &types.AttributeValueMemberS{Value: pk},
+			":now": &types.AttributeValueMemberS{Value: sortableTime(now)},
+		},
+		Limit:            aws.Int32(limit),
+		ScanIndexForward: aws.Bool(true),
+	}
+
+	var msgs []*OutboxMessage
+	for {
+		out, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query outbox: %w", err)
+		}
+
+		page := make([]*OutboxMessage, 0, len(out.Items))
+		for _, item := range out.Items {
+			var msg OutboxMessage
+			if err := attributevalue.UnmarshalMap(item, &msg); err != nil {
+				return nil, err
+			}
+			page = append(page, &msg)
+		}
+		holders, err := r.outboxKeyHolders(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range page {
+			if msg.Key != "" {
+				if holder, ok := holders[outboxKeyLockPK(msg.Topic, msg.Key)]; ok && holder != msg.MessageID {
+					continue
+				}
+			}
+			msgs = append(msgs, msg)
+			if len(msgs) == int(limit) {
+				return msgs, nil
+			}
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return msgs, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// outboxKeyHolders - 메시지들의 토픽/키 잠금 PK별로 잠금을 가진 MessageID (잠금이 없는 키는 빠짐)
+func (r *OrderRepository) outboxKeyHolders(ctx context.Context, msgs []*OutboxMessage) (map[string]string, error) {
+	seen := make(map[string]bool)
+	var keys []map[string]types.AttributeValue
+	for _, msg := range msgs {
+		if msg.Key == "" || seen[outboxKeyLockPK(msg.Topic, msg.Key)] {
+			continue
+		}
+		seen[outboxKeyLockPK(msg.Topic, msg.Key)] = true
+		keys = append(keys, outboxKeyLockKey(msg.Topic, msg.Key))
+	}
+
+	holders := make(map[string]string)
+	// BatchGetItem은 한 번에 100개 키까지
+	for start := 0; start < len(keys); start += 100 {
+		end := start + 100
+		if end > len(keys) {
+			end = len(keys)
+		}
+		request := map[string]types.KeysAndAttributes{
+			r.tableName: {Keys: keys[start:end], ProjectionExpression: aws.String("PK, MessageID")},
+		}
+		for len(request) > 0 {
+			out, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: request})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get outbox key locks: %w", err)
+			}
+			for _, item := range out.Responses[r.tableName] {
+				var lock struct {
+					PK        string
+					MessageID string
+				}
+				if err := attributevalue.UnmarshalMap(item, &lock); err != nil {
+					return nil, err
+				}
+				holders[lock.PK] = lock.MessageID
+			}
+			request = out.UnprocessedKeys
+		}
+	}
+	return holders, nil
+}
+
+// outboxKeyLockKey - 같은 토픽/키(주문 ID)의 메시지를 기록된 순서대로 하나씩 발행하기 위한 잠금 아이템
+// 선점한 메시지가 발행 완료될 때까지 유지되므로 재시도를 기다리는 동안에도 뒤의 메시지는 선점되지 않음
+func outboxKeyLockKey(topic, key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: outboxKeyLockPK(topic, key)},
+		"SK": &types.AttributeValueMemberS{Value: "LOCK"},
+	}
+}
+
+func outboxKeyLockPK(topic, key string) string {
+	return fmt.Sprintf("OUTBOX_KEY#%s#%s", topic, key)
+}
+
+// ClaimOutbox - 다른 릴레이가 같은 메시지를 동시에 발행하지 않도록 다음 시도 시각을 선점
+// 이미 다른 인스턴스가 선점했거나 발행을 끝낸 경우, 같은 키의 앞선 메시지가 아직 발행되지 않은 경우 false 반환
+func (r *OrderRepository) ClaimOutbox(ctx context.Context, msg *OutboxMessage, until time.Time) (bool, error) {
+	items := []types.TransactWriteItem{{
+		Update: &types.Update{
+			TableName: aws.String(r.tableName),
+			Key:       outboxKey(msg.MessageID),
+			UpdateExpression: aws.String(
+				"SET NextAttemptAt = :until, GSI1SK = :gsi1sk"),
+			ConditionExpression: aws.String("#status = :pending AND GSI1SK = :seen"),
+			ExpressionAttributeNames: map[string]string{
+				"#status": "Status",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":until":   mustMarshal(until),
+				":gsi1sk":  &types.AttributeValueMemberS{Value: sortableTime(until)},
+				":pending": &types.AttributeValueMemberS{Value: string(OutboxStatusPending)},
+				":seen":    &types.AttributeValueMemberS{Value: sortableTime(msg.NextAttemptAt)},
+			},
+		},
+	}}
+	if msg.Key != "" {
+		lock := outboxKeyLockKey(msg.Topic, msg.Key)
+		lock["MessageID"] = &types.AttributeValueMemberS{Value: msg.MessageID}
+		items = append(items, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           aws.String(r.tableName),
+				Item:                lock,
+				ConditionExpression: aws.String("attribute_not_exists(PK) OR MessageID = :id"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":id": &types.AttributeValueMemberS{Value: msg.MessageID},
+				},
+			},
+		})
+	}
+
+	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		if conditionFailedAt(err, 0) || conditionFailedAt(err, 1) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim outbox message: %w", err)
+	}
+
+	msg.NextAttemptAt = until
+	return true, nil
+}
+
+// MarkOutboxSent - 발행 완료 처리 후 GSI에서 제거하고 키 잠금을 풀어 다음 메시지가 선점될 수 있게 함
+func (r *OrderRepository) MarkOutboxSent(ctx context.Context, msg *OutboxMessage, sentAt time.Time) error {
+	items := []types.TransactWriteItem{{
+		Update: &types.Update{
+			TableName:        aws.String(r.tableName),
+			Key:              outboxKey(msg.MessageID),
+			UpdateExpression: aws.String("SET #status = :sent, ExpiresAt = :expires REMOVE GSI1PK, GSI1SK"),
+			ExpressionAttributeNames: map[string]string{
+				"#status": "Status",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":sent":    &types.AttributeValueMemberS{Value: string(OutboxStatusSent)},
+				":expires": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", sentAt.Add(outboxSentRetention).Unix())},
+			},
+		},
+	}}
+	if msg.Key != "" {
+		items = append(items, types.TransactWriteItem{
+			Delete: &types.Delete{
+				TableName:           aws.String(r.tableName),
+				Key:                 outboxKeyLockKey(msg.Topic, msg.Key),
+				ConditionExpression: aws.String("attribute_not_exists(PK) OR MessageID = :id"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":id": &types.AttributeValueMemberS{Value: msg.MessageID},
+				},
+			},
+		})
+	}
+
+	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if conditionFailedAt(err, 1) {
+		// 잠금 도입 전에 선점된 메시지라 다른 메시지가 잠금을 가진 경우 잠금은 그대로 두고 발행 완료만 기록
+		_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: items[:1],
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox message sent: %w", err)
+	}
+	return nil
+}
+
+// ScheduleOutboxRetry - 발행 실패 시 시도 횟수와 다음 시도 시각 기록
+func (r *OrderRepository) ScheduleOutboxRetry(ctx context.Context, messageID string, attempts int, next time.Time, lastErr string) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       outboxKey(messageID),
+		UpdateExpression: aws.String(
+			"SET Attempts = :attempts, NextAttemptAt = :next, GSI1SK = :gsi1sk, LastError = :err"),
+		ConditionExpression: aws.String("#status = :pending"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":attempts": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", attempts)},
+			":next":     mustMarshal(next),
+			":gsi1sk":   &types.AttributeValueMemberS{Value: sortableTime(next)},
+			":err":      &types.AttributeValueMemberS{Value: lastErr},
+			":pending":  &types.AttributeValueMemberS{Value: string(OutboxStatusPending)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule outbox retry: %w", err)
+	}
+	return nil
+}
+
+func outboxKey(messageID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: outboxPK(messageID)},
+		"SK": &types.AttributeValueMemberS{Value: "METADATA"},
+	}
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestOutboxPendingPKShardsByOrder(t *testing.T) {
+	used := make(map[string]bool)
+	for id := 0; id < 64; id++ {
+		// Snowflake처럼 하위 비트가 0인 ID
+		orderID := id << 12
+		first := outboxPendingPK(&OutboxMessage{MessageID: "a", Key: strconv.Itoa(orderID)})
+		second := outboxPendingPK(&OutboxMessage{MessageID: "b", Key: strconv.Itoa(orderID)})
+		if first != second {
+			t.Fatalf("order %d messages in %s and %s, want one shard", orderID, first, second)
+		}
+		if want := outboxShardPK(orderShard(orderID, outboxPendingShards)); first != want {
+			t.Errorf("order %d shard = %s, want %s", orderID, first, want)
+		}
+		used[first] = true
+	}
+	if len(used) != outboxPendingShards {
+		t.Errorf("messages spread over %d shards, want %d", len(used), outboxPendingShards)
+	}
+}
+
+func TestListPendingOutboxMergesShards(t *testing.T) {
+	repo := newTestDynamoRepository(t)
+	ctx := context.Background()
+	base := time.Now().UTC().Add(-time.Minute).Truncate(time.Millisecond)
+
+	var want []string
+	for i := 0; i < 6; i++ {
+		msg, err := NewOutboxMessage("order-events", "OrderStatusChanged", strconv.Itoa(i<<12), map[string]any{"order_id": i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg.CreatedAt = base.Add(time.Duration(i) * time.Second)
+		if err := repo.EnqueueOutbox(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, msg.MessageID)
+	}
+
+	// 샤드 도입 전에 기록된 메시지도 계속 조회됨
+	legacy, err := NewOutboxMessage("order-events", "OrderStatusChanged", "99", map[string]any{"order_id": 99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy.CreatedAt = base.Add(-time.Second)
+	put, err := repo.outboxPut(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	put.Item["GSI1PK"] = &types.AttributeValueMemberS{Value: outboxLegacyPendingPK}
+	if _, err := repo.client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(repo.tableName), Item: put.Item}); err != nil {
+		t.Fatal(err)
+	}
+	want = append([]string{legacy.MessageID}, want...)
+
+	msgs, err := repo.ListPendingOutbox(ctx, time.Now(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 5 {
+		t.Fatalf("got %d messages, want limit 5", len(msgs))
+	}
+	for i, msg := range msgs {
+		if msg.MessageID != want[i] {
+			t.Errorf("message %d = %s, want %s (oldest first across shards)", i, msg.MessageID, want[i])
+		}
+	}
+}
+
+func TestListPendingOutboxSkipsLockedKeys(t *testing.T) {
+	repo := newTestDynamoRepository(t)
+	ctx := context.Background()
+	base := time.Now().UTC().Add(-time.Minute).Truncate(time.Millisecond)
+
+	enqueue := func(key string, at time.Duration) *OutboxMessage {
+		t.Helper()
+		msg, err := NewOutboxMessage("order-events", "OrderStatusChanged", key, map[string]any{"key": key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg.CreatedAt = base.Add(at)
+		if err := repo.EnqueueOutbox(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+	// 주문 1의 첫 메시지가 재시도를 기다리는 동안 뒤 메시지는 한 페이지를 넘게 쌓여 있음
+	first := enqueue("1", 0)
+	for i := 1; i <= 4; i++ {
+		enqueue("1", time.Duration(i)*time.Second)
+	}
+	other := enqueue("2", 10*time.Second)
+
+	if ok, err := repo.ClaimOutbox(ctx, first, time.Now().Add(time.Minute)); err != nil || !ok {
+		t.Fatalf("claim = %v, %v", ok, err)
+	}
+	if err := repo.ScheduleOutboxRetry(ctx, first.MessageID, 1, time.Now().Add(time.Hour), "broker unavailable"); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := repo.ListPendingOutbox(ctx, time.Now(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || msgs[0].MessageID != other.MessageID {
+		t.Errorf("listed %d messages, want only order 2's message", len(msgs))
+	}
+}
@@ -0,0 +1,794 @@
+package repository
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// 여러 인스턴스가 동시에 기동해도 마이그레이션은 한 번만 적용
+const migrationLockID = 7_301_245_118
+
+// PostgresOrderRepository - DynamoDB 대신 PostgreSQL을 쓰는 환경의 OrderRepository 구현
+// 조건부 쓰기 실패, not-found 에러, 목록 정렬 순서를 DynamoDB 구현과 동일하게 맞춤
+type PostgresOrderRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresPool - DSN으로 커넥션 풀 생성 후 연결 확인
+func NewPostgresPool(ctx context.Context, dsn string, maxConns int32) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid POSTGRES_DSN: %w", err)
+	}
+	if maxConns > 0 {
+		poolCfg.MaxConns = maxConns
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	return pool, nil
+}
+
+func NewPostgresOrderRepository(pool *pgxpool.Pool) *PostgresOrderRepository {
+	return &PostgresOrderRepository{pool: pool}
+}
+
+// Migrate - 내장된 migrations/postgres/*.sql 중 적용되지 않은 파일을 이름 순으로 적용
+func (r *PostgresOrderRepository) Migrate(ctx context.Context) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT        PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	files, err := fs.Glob(postgresMigrations, "migrations/postgres/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		version := file[len("migrations/postgres/"):]
+		var applied bool
+		if err := conn.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&applied); err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", version, err)
+		}
+		if applied {
+			continue
+		}
+
+		sql, err := postgresMigrations.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, string(sql)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// CreateOrder - 주문, 항목, 멱등성 레코드, 변경 이력, 사가, 아웃박스 메시지를 하나의 트랜잭션으로 저장
+func (r *PostgresOrderRepository) CreateOrder(ctx context.Context, order *domain.Order, idem *IdempotencyRecord, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		lineHistory, err := marshalLineHistory(order.LineHistory)
+		if err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO orders (order_id, user_id, status, total_amount, currency, idempotency_key,
+				catalog_version, line_history, version, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (order_id) DO NOTHING`,
+			order.OrderID, order.UserID, order.Status, order.TotalAmount.Amount, order.TotalAmount.Currency,
+			order.IdempotencyKey, order.CatalogVersion, lineHistory, order.Version, order.CreatedAt, order.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert order: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrOrderAlreadyExists
+		}
+		if err := insertOrderItems(ctx, tx, order); err != nil {
+			return err
+		}
+
+		if idem != nil {
+			if err := putIdempotencyRecord(ctx, tx, idem, time.Now()); err != nil {
+				return err
+			}
+		}
+		if err := insertHistory(ctx, tx, history); err != nil {
+			return err
+		}
+		if err := writeSaga(ctx, tx, saga); err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, outbox)
+	})
+	if err == nil {
+		saga.done()
+	}
+	return err
+}
+
+func insertOrderItems(ctx context.Context, tx pgx.Tx, order *domain.Order) error {
+	for _, item := range order.Items {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO order_items (order_id, line_id, product_id, product_name, quantity,
+				price_amount, price_currency, status, cancelled_quantity)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			order.OrderID, item.LineID, item.ProductID, item.ProductName, item.Quantity,
+			item.Price.Amount, item.Price.Currency, item.Status, item.CancelledQuantity)
+		if err != nil {
+			return fmt.Errorf("failed to insert order item: %w", err)
+		}
+	}
+	return nil
+}
+
+// putIdempotencyRecord - 레코드가 없거나 만료된 경우에만 기록
+func putIdempotencyRecord(ctx context.Context, tx pgx.Tx, rec *IdempotencyRecord, now time.Time) error {
+	response, err := json.Marshal(rec.Response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO idempotency_records (user_id, idempotency_key, request_hash, order_id, response, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			order_id     = EXCLUDED.order_id,
+			response     = EXCLUDED.response,
+			created_at   = EXCLUDED.created_at,
+			expires_at   = EXCLUDED.expires_at
+		WHERE idempotency_records.expires_at <= $8`,
+		rec.UserID, rec.IdempotencyKey, rec.RequestHash, rec.OrderID, response, rec.CreatedAt, rec.ExpiresAt, now.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to put idempotency record: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrIdempotencyKeyExists
+	}
+	return nil
+}
+
+func insertHistory(ctx context.Context, tx pgx.Tx, entry *domain.HistoryEntry) error {
+	if entry == nil {
+		return nil
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order history: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO order_history (order_id, version, recorded_at, entry) VALUES ($1, $2, $3, $4)`,
+		entry.OrderID, entry.Version, entry.Timestamp, b)
+	if err != nil {
+		return fmt.Errorf("failed to insert order history: %w", err)
+	}
+	return nil
+}
+
+func insertOutbox(ctx context.Context, tx pgx.Tx, msgs []*OutboxMessage) error {
+	for _, msg := range msgs {
+		msg.Status = OutboxStatusPending
+		if msg.NextAttemptAt.IsZero() {
+			msg.NextAttemptAt = msg.CreatedAt
+		}
+		// PostgreSQL은 마이크로초까지 저장하므로 ClaimOutbox 비교값과 맞춤
+		msg.NextAttemptAt = msg.NextAttemptAt.Truncate(time.Microsecond)
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO outbox_messages (message_id, topic, event_type, key, payload, status,
+				attempts, last_error, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			msg.MessageID, msg.Topic, msg.EventType, msg.Key, msg.Payload, msg.Status,
+			msg.Attempts, msg.LastError, msg.NextAttemptAt, msg.CreatedAt)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("failed to write order transaction: outbox message %s already exists", msg.MessageID)
+			}
+			return fmt.Errorf("failed to insert outbox message: %w", err)
+		}
+	}
+	return nil
+}
+
+func marshalLineHistory(history []domain.LineChange) ([]byte, error) {
+	if history == nil {
+		history = []domain.LineChange{}
+	}
+	b, err := json.Marshal(history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal line history: %w", err)
+	}
+	return b, nil
+}
+
+const orderColumns = `order_id, user_id, status, total_amount, currency, idempotency_key,
+	catalog_version, line_history, version, created_at, updated_at`
+
+func scanOrder(row pgx.Row) (*domain.Order, error) {
+	var order domain.Order
+	var lineHistory []byte
+	err := row.Scan(&order.OrderID, &order.UserID, &order.Status, &order.TotalAmount.Amount, &order.TotalAmount.Currency,
+		&order.IdempotencyKey, &order.CatalogVersion, &lineHistory, &order.Version, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(lineHistory, &order.LineHistory); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal line history: %w", err)
+	}
+	if len(order.LineHistory) == 0 {
+		order.LineHistory = nil
+	}
+	order.Items = []domain.OrderItem{}
+	return &order, nil
+}
+
+// loadItems - 주문별 항목을 line_id 순서로 채움
+func (r *PostgresOrderRepository) loadItems(ctx context.Context, orders ...*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byID := make(map[int]*domain.Order, len(orders))
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		byID[order.OrderID] = order
+		ids = append(ids, int64(order.OrderID))
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT order_id, line_id, product_id, product_name, quantity, price_amount, price_currency,
+			status, cancelled_quantity
+		FROM order_items WHERE order_id = ANY($1) ORDER BY order_id, line_id`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to query order items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID int
+		var item domain.OrderItem
+		if err := rows.Scan(&orderID, &item.LineID, &item.ProductID, &item.ProductName, &item.Quantity,
+			&item.Price.Amount, &item.Price.Currency, &item.Status, &item.CancelledQuantity); err != nil {
+			return err
+		}
+		if order, ok := byID[orderID]; ok {
+			order.Items = append(order.Items, item)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *PostgresOrderRepository) GetOrder(ctx context.Context, id int) (*domain.Order, error) {
+	order, err := scanOrder(r.pool.QueryRow(ctx, "SELECT "+orderColumns+" FROM orders WHERE order_id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	if err := r.loadItems(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// UpdateOrderStatus - 현재 상태가 from일 때만 to로 변경하고, 변경 이력, 사가, 아웃박스 메시지를 같은 트랜잭션으로 기록
+func (r *PostgresOrderRepository) UpdateOrderStatus(ctx context.Context, id int, createdAt time.Time, from, to domain.OrderStatus, updatedAt time.Time, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE orders SET status = $3, updated_at = $4, version = version + 1
+			WHERE order_id = $1 AND status = $2`,
+			id, from, to, updatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrStatusConflict
+		}
+		if err := insertHistory(ctx, tx, history); err != nil {
+			return err
+		}
+		if err := writeSaga(ctx, tx, saga); err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, outbox)
+	})
+	if err == nil {
+		saga.done()
+	}
+	return err
+}
+
+// UpdateOrder - 저장된 version이 expectedVersion일 때만 주문과 항목 전체를 교체하고 version을 1 올림
+func (r *PostgresOrderRepository) UpdateOrder(ctx context.Context, order *domain.Order, expectedVersion int, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error {
+	order.Version = expectedVersion + 1
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		lineHistory, err := marshalLineHistory(order.LineHistory)
+		if err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `
+			UPDATE orders SET status = $3, total_amount = $4, currency = $5, catalog_version = $6,
+				line_history = $7, updated_at = $8, version = $9
+			WHERE order_id = $1 AND version = $2`,
+			order.OrderID, expectedVersion, order.Status, order.TotalAmount.Amount, order.TotalAmount.Currency,
+			order.CatalogVersion, lineHistory, order.UpdatedAt, order.Version)
+		if err != nil {
+			return fmt.Errorf("failed to save order: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return &VersionConflictError{OrderID: order.OrderID, ExpectedVersion: expectedVersion}
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM order_items WHERE order_id = $1", order.OrderID); err != nil {
+			return fmt.Errorf("failed to replace order items: %w", err)
+		}
+		if err := insertOrderItems(ctx, tx, order); err != nil {
+			return err
+		}
+		if err := insertHistory(ctx, tx, history); err != nil {
+			return err
+		}
+		if err := writeSaga(ctx, tx, saga); err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, outbox)
+	})
+	if err != nil {
+		order.Version = expectedVersion
+		return err
+	}
+	saga.done()
+	return nil
+}
+
+// GetOrdersByUser - 특정 사용자의 주문 목록 (최신순, (created_at, order_id) 키셋 페이지네이션)
+func (r *PostgresOrderRepository) GetOrdersByUser(ctx context.Context, q UserOrdersQuery) (*OrderPage, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE user_id = $1"
+	args := []any{q.UserID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if !q.CreatedAfter.IsZero() {
+		query += " AND created_at >= " + arg(q.CreatedAfter)
+	}
+	if !q.CreatedBefore.IsZero() {
+		query += " AND created_at <= " + arg(q.CreatedBefore)
+	}
+	if q.Status != "" {
+		query += " AND status = " + arg(q.Status)
+	}
+	if len(q.StartKey) > 0 {
+		createdAt, err := time.Parse(time.RFC3339Nano, q.StartKey["created_at"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start key: %w", err)
+		}
+		orderID, err := strconv.Atoi(q.StartKey["order_id"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start key: %w", err)
+		}
+		query += fmt.Sprintf(" AND (created_at, order_id) < (%s, %s)", arg(createdAt), arg(orderID))
+	}
+	query += " ORDER BY created_at DESC, order_id DESC"
+	if q.Limit > 0 {
+		// 다음 페이지가 있는지 확인하기 위해 한 건 더 조회
+		query += " LIMIT " + arg(q.Limit+1)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orders: %w", err)
+	}
+	defer rows.Close()
+
+	page := &OrderPage{Orders: []*domain.Order{}}
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Orders = append(page.Orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if q.Limit > 0 && len(page.Orders) > int(q.Limit) {
+		page.Orders = page.Orders[:q.Limit]
+		last := page.Orders[len(page.Orders)-1]
+		page.NextKey = map[string]string{
+			"created_at": last.CreatedAt.UTC().Format(time.RFC3339Nano),
+			"order_id":   strconv.Itoa(last.OrderID),
+		}
+	}
+	if err := r.loadItems(ctx, page.Orders...); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ListOrdersByStatus - 상태/생성일 범위 주문 목록 ((created_at, order_id) 키셋 페이지네이션)
+func (r *PostgresOrderRepository) ListOrdersByStatus(ctx context.Context, q StatusOrdersQuery) (*OrderPage, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE TRUE"
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if q.Status != "" {
+		query += " AND status = " + arg(q.Status)
+	}
+	if !q.CreatedAfter.IsZero() {
+		query += " AND created_at >= " + arg(q.CreatedAfter)
+	}
+	if !q.CreatedBefore.IsZero() {
+		query += " AND created_at <= " + arg(q.CreatedBefore)
+	}
+	op, dir := ">", "ASC"
+	if q.Descending {
+		op, dir = "<", "DESC"
+	}
+	if len(q.StartKey) > 0 {
+		createdAt, err := time.Parse(time.RFC3339Nano, q.StartKey["created_at"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start key: %w", err)
+		}
+		orderID, err := strconv.Atoi(q.StartKey["order_id"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start key: %w", err)
+		}
+		query += fmt.Sprintf(" AND (created_at, order_id) %s (%s, %s)", op, arg(createdAt), arg(orderID))
+	}
+	query += fmt.Sprintf(" ORDER BY created_at %s, order_id %s", dir, dir)
+	if q.Limit > 0 {
+		query += " LIMIT " + arg(q.Limit+1)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orders: %w", err)
+	}
+	defer rows.Close()
+
+	page := &OrderPage{Orders: []*domain.Order{}}
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Orders = append(page.Orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if q.Limit > 0 && len(page.Orders) > int(q.Limit) {
+		page.Orders = page.Orders[:q.Limit]
+		last := page.Orders[len(page.Orders)-1]
+		page.NextKey = map[string]string{
+			"created_at": last.CreatedAt.UTC().Format(time.RFC3339Nano),
+			"order_id":   strconv.Itoa(last.OrderID),
+		}
+	}
+	if err := r.loadItems(ctx, page.Orders...); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (r *PostgresOrderRepository) GetIdempotencyRecord(ctx context.Context, userID, key string) (*IdempotencyRecord, error) {
+	rec := IdempotencyRecord{UserID: userID, IdempotencyKey: key}
+	var response []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT request_hash, order_id, response, created_at, expires_at
+		FROM idempotency_records WHERE user_id = $1 AND idempotency_key = $2`, userID, key).
+		Scan(&rec.RequestHash, &rec.OrderID, &response, &rec.CreatedAt, &rec.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIdempotencyRecordNotFound
+		}
+		return nil, err
+	}
+	if rec.Expired(time.Now()) {
+		return nil, ErrIdempotencyRecordNotFound
+	}
+	if err := json.Unmarshal(response, &rec.Response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+	}
+	return &rec, nil
+}
+
+// ListOrderHistory - 주문의 변경 이력 전체 (오래된 순)
+func (r *PostgresOrderRepository) ListOrderHistory(ctx context.Context, orderID int) ([]*domain.HistoryEntry, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT entry FROM order_history WHERE order_id = $1 ORDER BY recorded_at, version`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.HistoryEntry{}
+	for rows.Next() {
+		var b []byte
+		if err := rows.Scan(&b); err != nil {
+			return nil, err
+		}
+		var entry domain.HistoryEntry
+		if err := json.Unmarshal(b, &entry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal order history: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+// EnqueueOutbox - 주문 변경 없이 메시지만 발행 대기열에 기록 (이벤트 재발행 등)
+func (r *PostgresOrderRepository) EnqueueOutbox(ctx context.Context, msgs ...*OutboxMessage) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		return insertOutbox(ctx, tx, msgs)
+	})
+}
+
+// ListPendingOutbox - 발행 시각이 도래한 대기 메시지를 오래된 순으로 조회
+// 같은 토픽/키의 앞선 메시지가 재시도를 기다리거나 발행 중이면 선점할 수 없으므로 제외
+func (r *PostgresOrderRepository) ListPendingOutbox(ctx context.Context, now time.Time, limit int32) ([]*OutboxMessage, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT message_id, topic, event_type, key, payload, status, attempts, last_error, next_attempt_at, created_at
+		FROM outbox_messages m
+		WHERE status = $1 AND next_attempt_at <= $2
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_messages e
+				WHERE m.key <> '' AND e.topic = m.topic AND e.key = m.key
+					AND e.status = 'PENDING' AND e.seq < m.seq AND e.next_attempt_at > $2)
+		ORDER BY next_attempt_at, seq
+		LIMIT $3`, OutboxStatusPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	msgs := []*OutboxMessage{}
+	for rows.Next() {
+		var msg OutboxMessage
+		if err := rows.Scan(&msg.MessageID, &msg.Topic, &msg.EventType, &msg.Key, &msg.Payload, &msg.Status,
+			&msg.Attempts, &msg.LastError, &msg.NextAttemptAt, &msg.CreatedAt); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, &msg)
+	}
+	return msgs, rows.Err()
+}
+
+// ClaimOutbox - 조회 이후 다른 릴레이가 선점하지 않았고 같은 토픽/키의 앞선 메시지가 모두 발행되었을 때만
+// 다음 시도 시각을 until로 옮김 (재시도를 기다리는 메시지 뒤의 메시지는 먼저 발행되지 않음)
+func (r *PostgresOrderRepository) ClaimOutbox(ctx context.Context, msg *OutboxMessage, until time.Time) (bool, error) {
+	until = until.Truncate(time.Microsecond)
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE outbox_messages m SET next_attempt_at = $3
+		WHERE m.message_id = $1 AND m.status = 'PENDING' AND m.next_attempt_at = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_messages e
+				WHERE m.key <> '' AND e.topic = m.topic AND e.key = m.key
+					AND e.status = 'PENDING' AND e.seq < m.seq)`,
+		msg.MessageID, msg.NextAttemptAt, until)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim outbox message: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	msg.NextAttemptAt = until
+	return true, nil
+}
+
+// MarkOutboxSent - 발행 완료 처리, 보관 기간이 지난 발행 완료 메시지는 함께 정리
+func (r *PostgresOrderRepository) MarkOutboxSent(ctx context.Context, msg *OutboxMessage, sentAt time.Time) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+			UPDATE outbox_messages SET status = $2, sent_at = $3 WHERE message_id = $1`,
+			msg.MessageID, OutboxStatusSent, sentAt); err != nil {
+			return fmt.Errorf("failed to mark outbox message sent: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM outbox_messages WHERE status = $1 AND sent_at < $2`,
+			OutboxStatusSent, sentAt.Add(-outboxSentRetention)); err != nil {
+			return fmt.Errorf("failed to clean up sent outbox messages: %w", err)
+		}
+		return nil
+	})
+}
+
+// ScheduleOutboxRetry - 발행 실패 시 시도 횟수와 다음 시도 시각 기록
+func (r *PostgresOrderRepository) ScheduleOutboxRetry(ctx context.Context, messageID string, attempts int, next time.Time, lastErr string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE outbox_messages SET attempts = $2, next_attempt_at = $3, last_error = $4
+		WHERE message_id = $1 AND status = 'PENDING'`,
+		messageID, attempts, next.Truncate(time.Microsecond), lastErr)
+	if err != nil {
+		return fmt.Errorf("failed to schedule outbox retry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to schedule outbox retry: message %s is not pending", messageID)
+	}
+	return nil
+}
+
+// sagaDueAt - DynamoDB 구현의 SAGA#DUE 노출 조건과 같음 (처리할 일이 없으면 nil)
+func sagaDueAt(saga *domain.Saga) *time.Time {
+	switch {
+	case saga.Status == domain.SagaStatusRunning:
+		return &saga.Deadline
+	case !saga.OrderUpdated:
+		return &saga.UpdatedAt
+	}
+	return nil
+}
+
+// writeSaga - SagaWrite의 조건으로 사가를 생성 또는 교체 (w가 nil이면 아무것도 하지 않음)
+func writeSaga(ctx context.Context, tx pgx.Tx, w *SagaWrite) error {
+	if w == nil {
+		return nil
+	}
+	saga := *w.Saga
+	saga.Version = w.nextVersion()
+	data, err := json.Marshal(&saga)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saga: %w", err)
+	}
+
+	var tag pgconn.CommandTag
+	if w.Create {
+		tag, err = tx.Exec(ctx, `
+			INSERT INTO sagas (order_id, data, version, due_at) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (order_id) DO NOTHING`,
+			saga.OrderID, data, saga.Version, sagaDueAt(&saga))
+	} else {
+		tag, err = tx.Exec(ctx, `
+			UPDATE sagas SET data = $3, version = $4, due_at = $5
+			WHERE order_id = $1 AND version = $2`,
+			saga.OrderID, w.ExpectedVersion, data, saga.Version, sagaDueAt(&saga))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save saga: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return w.conflict()
+	}
+	return nil
+}
+
+func (r *PostgresOrderRepository) CreateSaga(ctx context.Context, saga *domain.Saga) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		return writeSaga(ctx, tx, &SagaWrite{Saga: saga, Create: true})
+	})
+}
+
+func (r *PostgresOrderRepository) GetSaga(ctx context.Context, orderID int) (*domain.Saga, error) {
+	var data []byte
+	err := r.pool.QueryRow(ctx, "SELECT data FROM sagas WHERE order_id = $1", orderID).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSagaNotFound
+		}
+		return nil, fmt.Errorf("failed to get saga: %w", err)
+	}
+
+	var saga domain.Saga
+	if err := json.Unmarshal(data, &saga); err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
+
+// SaveSaga - 버전이 expectedVersion일 때만 사가를 교체하고, 보상 이벤트를 같은 트랜잭션으로 기록
+func (r *PostgresOrderRepository) SaveSaga(ctx context.Context, saga *domain.Saga, expectedVersion int, outbox ...*OutboxMessage) error {
+	w := &SagaWrite{Saga: saga, ExpectedVersion: expectedVersion}
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := writeSaga(ctx, tx, w); err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, outbox)
+	})
+	if err == nil {
+		w.done()
+	}
+	return err
+}
+
+// ListDueSagas - 타임아웃이 지났거나 주문 반영이 남은 사가 조회
+func (r *PostgresOrderRepository) ListDueSagas(ctx context.Context, now time.Time, limit int32) ([]*domain.Saga, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT data FROM sagas WHERE due_at <= $1 ORDER BY due_at LIMIT $2`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due sagas: %w", err)
+	}
+	defer rows.Close()
+
+	sagas := []*domain.Saga{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var saga domain.Saga
+		if err := json.Unmarshal(data, &saga); err != nil {
+			return nil, err
+		}
+		sagas = append(sagas, &saga)
+	}
+	return sagas, rows.Err()
+}
+
+// AcquireLease - 리스가 없거나 만료되었거나 이미 holder가 가진 경우 ttl만큼 (재)획득
+func (r *PostgresOrderRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO leases (name, holder, lease_until) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, lease_until = EXCLUDED.lease_until
+		WHERE leases.holder = EXCLUDED.holder OR leases.lease_until < $4`,
+		name, holder, now.Add(ttl), now)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ReleaseLease - holder가 가진 리스를 반납 (다른 인스턴스가 가져간 리스는 그대로 둠)
+func (r *PostgresOrderRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	if _, err := r.pool.Exec(ctx, "DELETE FROM leases WHERE name = $1 AND holder = $2", name, holder); err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+var (
+	ErrSagaNotFound        = errors.New("saga not found")
+	ErrSagaExists          = errors.New("saga already exists")
+	ErrSagaVersionConflict = errors.New("saga modified concurrently")
+)
+
+// 타임아웃 또는 주문 반영이 필요한 사가는 GSI1의 이 파티션에 노출
+const sagaDuePK = "SAGA#DUE"
+
+// SagaWrite - 주문 변경과 같은 트랜잭션으로 기록할 사가
+// Create면 사가가 없을 때만 생성(있으면 ErrSagaExists), 아니면 저장된 버전이 ExpectedVersion일 때만 교체(ErrSagaVersionConflict)
+type SagaWrite struct {
+	Saga            *domain.Saga
+	Create          bool
+	ExpectedVersion int
+}
+
+// SagaPlan - 검증을 마친 변경 전후 주문으로 같은 트랜잭션에 기록할 사가와 추가 아웃박스 메시지를 만듦
+// 사가를 바꿀 필요가 없으면 nil SagaWrite 반환
+type SagaPlan func(before, after *domain.Order) (*SagaWrite, []*OutboxMessage, error)
+
+// nextVersion - 기록 후의 사가 버전
+func (w *SagaWrite) nextVersion() int {
+	if w.Create {
+		return w.Saga.Version
+	}
+	return w.ExpectedVersion + 1
+}
+
+// done - 기록에 성공하면 사가 버전을 기록 후 버전으로 맞춤 (w가 nil이면 아무것도 하지 않음)
+func (w *SagaWrite) done() {
+	if w != nil {
+		w.Saga.Version = w.nextVersion()
+	}
+}
+
+// conflict - 사가 조건이 맞지 않아 트랜잭션이 취소되었을 때의 에러
+func (w *SagaWrite) conflict() error {
+	if w.Create {
+		return ErrSagaExists
+	}
+	return ErrSagaVersionConflict
+}
+
+func sagaKey(orderID int) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("ORDER#%d", orderID)},
+		"SK": &types.AttributeValueMemberS{Value: "SAGA"},
+	}
+}
+
+func sagaItem(saga *domain.Saga) (map[string]types.AttributeValue, error) {
+	av, err := attributevalue.MarshalMap(saga)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal saga: %w", err)
+	}
+	for k, v := range sagaKey(saga.OrderID) {
+		av[k] = v
+	}
+
+	switch {
+	case saga.Status == domain.SagaStatusRunning:
+		av["GSI1PK"] = &types.AttributeValueMemberS{Value: sagaDuePK}
+		av["GSI1SK"] = &types.AttributeValueMemberS{Value: sortableTime(saga.Deadline)}
+	case !saga.OrderUpdated:
+		// 종료되었지만 주문 상태 반영 전 - 즉시 재처리 대상
+		av["GSI1PK"] = &types.AttributeValueMemberS{Value: sagaDuePK}
+		av["GSI1SK"] = &types.AttributeValueMemberS{Value: sortableTime(saga.UpdatedAt)}
+	}
+	return av, nil
+}
+
+// sagaPut - SagaWrite의 조건이 붙은 사가 Put (기록 후 버전으로 저장)
+func (r *OrderRepository) sagaPut(w *SagaWrite) (*types.Put, error) {
+	saga := *w.Saga
+	saga.Version = w.nextVersion()
+	av, err := sagaItem(&saga)
+	if err != nil {
+		return nil, err
+	}
+
+	put := &types.Put{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("Version = :expected"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":expected": &types.AttributeValueMemberN{Value: strconv.Itoa(w.ExpectedVersion)},
+		},
+	}
+	if w.Create {
+		put.ConditionExpression = aws.String("attribute_not_exists(PK)")
+		put.ExpressionAttributeValues = nil
+	}
+	return put, nil
+}
+
+// appendSaga - 사가 Put을 트랜잭션에 추가하고 그 위치 반환 (w가 nil이면 -1)
+func (r *OrderRepository) appendSaga(items []types.TransactWriteItem, w *SagaWrite) ([]types.TransactWriteItem, int, error) {
+	if w == nil {
+		return items, -1, nil
+	}
+	put, err := r.sagaPut(w)
+	if err != nil {
+		return nil, -1, err
+	}
+	return append(items, types.TransactWriteItem{Put: put}), len(items), nil
+}
+
+func (r *OrderRepository) CreateSaga(ctx context.Context, saga *domain.Saga) error {
+	put, err := r.sagaPut(&SagaWrite{Saga: saga, Create: true})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           put.TableName,
+		Item:                put.Item,
+		ConditionExpression: put.ConditionExpression,
+	})
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return ErrSagaExists
+		}
+		return fmt.Errorf("failed to create saga: %w", err)
+	}
+	return nil
+}
+
+func (r *OrderRepository) GetSaga(ctx context.Context, orderID int) (*domain.Saga, error) {
+	out, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            sagaKey(orderID),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saga: %w", err)
+	}
+	if len(out.Item) == 0 {
+		return nil, ErrSagaNotFound
+	}
+
+	var saga domain.Saga
+	if err := attributevalue.UnmarshalMap(out.Item, &saga); err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
+
+// SaveSaga - 버전이 expectedVersion일 때만 사가를 교체하고, 보상 이벤트를 같은 트랜잭션으로 기록
+func (r *OrderRepository) SaveSaga(ctx context.Context, saga *domain.Saga, expectedVersion int, outbox ...*OutboxMessage) error {
+	items, _, err := r.appendSaga(nil, &SagaWrite{Saga: saga, ExpectedVersion: expectedVersion})
+	if err != nil {
+		return err
+	}
+	if items, err = r.appendOutbox(items, outbox); err != nil {
+		return err
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		if conditionFailedAt(err, 0) {
+			return ErrSagaVersionConflict
+		}
+		return fmt.Errorf("failed to save saga: %w", err)
+	}
+	saga.Version = expectedVersion + 1
+	return nil
+}
+
+// ListDueSagas - 타임아웃이 지났거나 주문 반영이 남은 사가 조회
+func (r *OrderRepository) ListDueSagas(ctx context.Context, now time.Time, limit int32) ([]*domain.Saga, error) {
+	out, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("GSI1"),
+		KeyConditionExpression: aws.String("GSI1PK = :pk AND GSI1SK <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":  &types.AttributeValueMemberS{Value: sagaDuePK},
+			":now": &types.AttributeValueMemberS{Value: sortableTime(now)},
+		},
+		Limit:            aws.Int32(limit),
+		ScanIndexForward: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due sagas: %w", err)
+	}
+
+	sagas := make([]*domain.Saga, 0, len(out.Items))
+	for _, item := range out.Items {
+		var saga domain.Saga
+		if err := attributevalue.UnmarshalMap(item, &saga); err != nil {
+			return nil, err
+		}
+		sagas = append(sagas, &saga)
+	}
+	return sagas, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// ttlAttribute - 멱등성 레코드, 발행 완료 아웃박스가 만료 시각(epoch seconds)을 기록하는 속성
+const ttlAttribute = "ExpiresAt"
+
+// 테이블/인덱스 생성 완료 대기 시간
+const schemaWaitTimeout = 5 * time.Minute
+
+// indexDef - 테이블에 있어야 하는 GSI (새 인덱스는 여기에 추가하면 EnsureSchema가 생성)
+type indexDef struct {
+	Name         string
+	PartitionKey string
+	SortKey      string
+}
+
+var orderTableIndexes = []indexDef{
+	{Name: "GSI1", PartitionKey: "GSI1PK", SortKey: "GSI1SK"},
+	{Name: "GSI2", PartitionKey: "GSI2PK", SortKey: "GSI2SK"},
+}
+
+func (d indexDef) keySchema() []types.KeySchemaElement {
+	return []types.KeySchemaElement{
+		{AttributeName: aws.String(d.PartitionKey), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String(d.SortKey), KeyType: types.KeyTypeRange},
+	}
+}
+
+func (d indexDef) attributes() []types.AttributeDefinition {
+	return []types.AttributeDefinition{
+		{AttributeName: aws.String(d.PartitionKey), AttributeType: types.ScalarAttributeTypeS},
+		{AttributeName: aws.String(d.SortKey), AttributeType: types.ScalarAttributeTypeS},
+	}
+}
+
+// EnsureSchema - 주문 테이블, GSI, TTL 설정이 없으면 만들고 있으면 그대로 둠 (여러 번 실행해도 안전)
+// 테이블은 PAY_PER_REQUEST로 생성하며, 이미 있는 테이블의 키/과금 설정은 바꾸지 않음
+func EnsureSchema(ctx context.Context, client *dynamodb.Client, tableName string, logger *zap.Logger) error {
+	desc, err := describeTable(ctx, client, tableName)
+	if err != nil {
+		return err
+	}
+	if desc == nil {
+		if err := createTable(ctx, client, tableName); err != nil {
+			return err
+		}
+		logger.Info("DynamoDB table created", zap.String("table", tableName))
+		if desc, err = describeTable(ctx, client, tableName); err != nil {
+			return err
+		}
+	}
+
+	existing := make(map[string]bool, len(desc.GlobalSecondaryIndexes))
+	for _, gsi := range desc.GlobalSecondaryIndexes {
+		existing[aws.ToString(gsi.IndexName)] = true
+	}
+	// DynamoDB는 UpdateTable 한 번에 GSI 하나만 생성할 수 있음
+	for _, idx := range orderTableIndexes {
+		if existing[idx.Name] {
+			continue
+		}
+		if err := createIndex(ctx, client, tableName, idx); err != nil {
+			return err
+		}
+		logger.Info("DynamoDB index created", zap.String("table", tableName), zap.String("index", idx.Name))
+	}
+
+	return ensureTTL(ctx, client, tableName, logger)
+}
+
+// describeTable - 테이블이 없으면 nil
+func describeTable(ctx context.Context, client *dynamodb.Client, tableName string) (*types.TableDescription, error) {
+	out, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe table %s: %w", tableName, err)
+	}
+	return out.Table, nil
+}
+
+func createTable(ctx context.Context, client *dynamodb.Client, tableName string) error {
+	attrs := []types.AttributeDefinition{
+		{AttributeName: aws.String("PK"), AttributeType: types.ScalarAttributeTypeS},
+		{AttributeName: aws.String("SK"), AttributeType: types.ScalarAttributeTypeS},
+	}
+	var gsis []types.GlobalSecondaryIndex
+	for _, idx := range orderTableIndexes {
+		attrs = append(attrs, idx.attributes()...)
+		gsis = append(gsis, types.GlobalSecondaryIndex{
+			IndexName:  aws.String(idx.Name),
+			KeySchema:  idx.keySchema(),
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+
+	_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            aws.String(tableName),
+		AttributeDefinitions: attrs,
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("PK"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("SK"), KeyType: types.KeyTypeRange},
+		},
+		GlobalSecondaryIndexes: gsis,
+		BillingMode:            types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		// 다른 인스턴스가 먼저 생성 중
+		var inUse *types.ResourceInUseException
+		if !errors.As(err, &inUse) {
+			return fmt.Errorf("failed to create table %s: %w", tableName, err)
+		}
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, schemaWaitTimeout); err != nil {
+		return fmt.Errorf("table %s did not become active: %w", tableName, err)
+	}
+	return nil
+}
+
+func createIndex(ctx context.Context, client *dynamodb.Client, tableName string, idx indexDef) error {
+	_, err := client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:            aws.String(tableName),
+		AttributeDefinitions: idx.attributes(),
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+			{Create: &types.CreateGlobalSecondaryIndexAction{
+				IndexName:  aws.String(idx.Name),
+				KeySchema:  idx.keySchema(),
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			}},
+		},
+	})
+	if err != nil {
+		var inUse *types.ResourceInUseException
+		if !errors.As(err, &inUse) {
+			return fmt.Errorf("failed to create index %s on %s: %w", idx.Name, tableName, err)
+		}
+	}
+	return waitIndexActive(ctx, client, tableName, idx.Name)
+}
+
+// waitIndexActive - 인덱스 백필이 끝나 ACTIVE가 될 때까지 대기
+func waitIndexActive(ctx context.Context, client *dynamodb.Client, tableName, indexName string) error {
+	ctx, cancel := context.WithTimeout(ctx, schemaWaitTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		desc, err := describeTable(ctx, client, tableName)
+		if err != nil {
+			return err
+		}
+		if desc != nil {
+			for _, gsi := range desc.GlobalSecondaryIndexes {
+				if aws.ToString(gsi.IndexName) == indexName && gsi.IndexStatus == types.IndexStatusActive {
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("index %s on %s did not become active: %w", indexName, tableName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func ensureTTL(ctx context.Context, client *dynamodb.Client, tableName string, logger *zap.Logger) error {
+	out, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(tableName)})
+	if err != nil {
+		return fmt.Errorf("failed to describe TTL of %s: %w", tableName, err)
+	}
+	if desc := out.TimeToLiveDescription; desc != nil {
+		switch desc.TimeToLiveStatus {
+		case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+			if name := aws.ToString(desc.AttributeName); name != ttlAttribute {
+				logger.Warn("DynamoDB TTL is enabled on a different attribute",
+					zap.String("table", tableName),
+					zap.String("attribute", name),
+					zap.String("expected", ttlAttribute))
+			}
+			return nil
+		}
+	}
+
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(ttlAttribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable TTL on %s: %w", tableName, err)
+	}
+	logger.Info("DynamoDB TTL enabled", zap.String("table", tableName), zap.String("attribute", ttlAttribute))
+	return nil
+}
@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+// GSI2 - 상태별 주문 생성일 인덱스
+// 한 상태(특히 PENDING)에 쓰기가 몰리지 않도록 OrderID로 파티션을 나눔
+// 샤드 수를 바꾸면 기존 아이템의 GSI2PK를 모두 다시 써야 함
+const statusIndexShards = 8
+
+// statusIndexPK - STATUS#<상태>#<샤드>
+func statusIndexPK(status domain.OrderStatus, orderID int) string {
+	return statusShardPK(status, orderShard(orderID, statusIndexShards))
+}
+
+// orderShard - 주문 ID의 FNV-1a 해시로 샤드를 정함
+// Snowflake ID의 하위 비트는 시퀀스라 대부분 0이므로 orderID%n을 쓰면 한 샤드에 몰림
+func orderShard(orderID, shards int) int {
+	return keyShard(strconv.Itoa(orderID), shards)
+}
+
+// keyShard - 문자열 키의 FNV-1a 해시로 샤드를 정함 (주문 ID 문자열이면 orderShard와 같음)
+func keyShard(key string, shards int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(shards))
+}
+
+func statusShardPK(status domain.OrderStatus, shard int) string {
+	return fmt.Sprintf("STATUS#%s#%d", status, shard)
+}
+
+// statusIndexSK - <생성 시각>#<OrderID>, 샤드를 합쳐도 순서가 유일하게 정해짐
+func statusIndexSK(createdAt time.Time, orderID int) string {
+	return fmt.Sprintf("%s#%019d", sortableTime(createdAt), orderID)
+}
+
+// StatusOrdersQuery - 운영용 상태/생성일 주문 조회 조건
+type StatusOrdersQuery struct {
+	// 비어 있으면 모든 상태
+	Status        domain.OrderStatus
+	CreatedAfter  time.Time // 포함
+	CreatedBefore time.Time // 포함
+	Limit         int32
+	// true면 최신순, 기본은 오래된 순
+	Descending bool
+	// 이전 페이지의 NextKey
+	StartKey map[string]string
+}
+
+var allOrderStatuses = []domain.OrderStatus{
+	domain.OrderStatusPending,
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusShipped,
+	domain.OrderStatusDelivered,
+	domain.OrderStatusCancelled,
+}
+
+// statusIndexRange - GSI2SK 조회 범위 (StartKey가 있으면 경계값 포함, 호출자가 같은 키를 건너뜀)
+func statusIndexRange(q StatusOrdersQuery) (from, to string) {
+	// 키 조건에는 빈 문자열을 쓸 수 없으므로 모든 시각보다 앞서는 "0"부터
+	from, to = "0", "~"
+	if !q.CreatedAfter.IsZero() {
+		from = sortableTime(q.CreatedAfter)
+	}
+	if !q.CreatedBefore.IsZero() {
+		to = sortableTime(q.CreatedBefore) + "#~"
+	}
+	if last := q.StartKey["GSI2SK"]; last != "" {
+		if q.Descending {
+			to = last
+		} else {
+			from = last
+		}
+	}
+	return from, to
+}
+
+// ListOrdersByStatus - 상태(없으면 전체 상태)의 모든 샤드를 병렬로 조회해 생성일 순으로 병합
+func (r *OrderRepository) ListOrdersByStatus(ctx context.Context, q StatusOrdersQuery) (*OrderPage, error) {
+	statuses := allOrderStatuses
+	if q.Status != "" {
+		statuses = []domain.OrderStatus{q.Status}
+	}
+	from, to := statusIndexRange(q)
+
+	type result struct {
+		orders []*domain.Order
+		err    error
+	}
+	results := make([]result, len(statuses)*statusIndexShards)
+	var wg sync.WaitGroup
+	for i, status := range statuses {
+		for shard := 0; shard < statusIndexShards; shard++ {
+			idx := i*statusIndexShards + shard
+			pk := statusShardPK(status, shard)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				orders, err := r.queryStatusShard(ctx, pk, from, to, q)
+				results[idx] = result{orders: orders, err: err}
+			}()
+		}
+	}
+	wg.Wait()
+
+	var merged []*domain.Order
+	for _, res := range results {
+		if res.err != nil {
+			return nil, res.err
+		}
+		merged = append(merged, res.orders...)
+	}
+	return mergeStatusPage(merged, q), nil
+}
+
+// queryStatusShard - 한 샤드에서 경계값을 제외하고 최대 Limit+1건 (다음 페이지 여부 확인용)
+func (r *OrderRepository) queryStatusShard(ctx context.Context, pk, from, to string, q StatusOrdersQuery) ([]*domain.Order, error) {
+	last := q.StartKey["GSI2SK"]
+	want := int(q.Limit) + 1
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("GSI2"),
+		KeyConditionExpression: aws.String("GSI2PK = :pk AND GSI2SK BETWEEN :from AND :to"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":   &types.AttributeValueMemberS{Value: pk},
+			":from": &types.AttributeValueMemberS{Value: from},
+			":to":   &types.AttributeValueMemberS{Value: to},
+		},
+		ScanIndexForward: aws.Bool(!q.Descending),
+	}
+	if q.Limit > 0 {
+		input.Limit = aws.Int32(int32(want))
+	}
+
+	var orders []*domain.Order
+	for {
+		out, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query status index: %w", err)
+		}
+		for _, item := range out.Items {
+			if sk, ok := item["GSI2SK"].(*types.AttributeValueMemberS); ok && sk.Value == last {
+				continue
+			}
+			var order domain.Order
+			if err := unmarshalOrder(item, &order); err != nil {
+				return nil, err
+			}
+			orders = append(orders, &order)
+		}
+		if len(out.LastEvaluatedKey) == 0 || (q.Limit > 0 && len(orders) >= want) {
+			return orders, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// mergeStatusPage - 샤드별 결과를 GSI2SK 순서로 합치고 Limit건으로 자름
+func mergeStatusPage(orders []*domain.Order, q StatusOrdersQuery) *OrderPage {
+	sort.Slice(orders, func(i, j int) bool {
+		a := statusIndexSK(orders[i].CreatedAt, orders[i].OrderID)
+		b := statusIndexSK(orders[j].CreatedAt, orders[j].OrderID)
+		if q.Descending {
+			return a > b
+		}
+		return a < b
+	})
+
+	page := &OrderPage{Orders: orders}
+	if page.Orders == nil {
+		page.Orders = []*domain.Order{}
+	}
+	if q.Limit > 0 && len(orders) > int(q.Limit) {
+		page.Orders = orders[:q.Limit]
+		last := page.Orders[len(page.Orders)-1]
+		page.NextKey = map[string]string{"GSI2SK": statusIndexSK(last.CreatedAt, last.OrderID)}
+	}
+	return page
+}
+
+// BackfillStatusIndex - GSI2 속성(GSI2PK/GSI2SK)이 없거나 샤드가 현재 규칙(orderShard)과 다른 주문의 상태 인덱스 키를 다시 씀
+// 스캔 이후 상태나 인덱스 키가 바뀐 주문은 건너뛰고(이미 새 키로 기록됨) 갱신한 건수를 반환
+func (r *OrderRepository) BackfillStatusIndex(ctx context.Context) (int, error) {
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(r.tableName),
+		FilterExpression: aws.String("SK = :meta AND begins_with(PK, :order)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":meta":  &types.AttributeValueMemberS{Value: "METADATA"},
+			":order": &types.AttributeValueMemberS{Value: "ORDER#"},
+		},
+	}
+
+	updated := 0
+	for {
+		out, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return updated, fmt.Errorf("failed to scan orders: %w", err)
+		}
+		for _, item := range out.Items {
+			var order domain.Order
+			if err := unmarshalOrder(item, &order); err != nil {
+				return updated, err
+			}
+			pk := statusIndexPK(order.Status, order.OrderID)
+			values := map[string]types.AttributeValue{
+				":gsi2pk": &types.AttributeValueMemberS{Value: pk},
+				":gsi2sk": &types.AttributeValueMemberS{Value: statusIndexSK(order.CreatedAt, order.OrderID)},
+				":status": &types.AttributeValueMemberS{Value: string(order.Status)},
+			}
+			condition := "#status = :status AND attribute_not_exists(GSI2PK)"
+			if current, ok := item["GSI2PK"].(*types.AttributeValueMemberS); ok {
+				// 백필 전에 상태가 바뀐 기존 주문은 GSI2PK만 있고 GSI2SK가 없을 수 있음
+				if _, indexed := item["GSI2SK"]; indexed && current.Value == pk {
+					continue
+				}
+				condition = "#status = :status AND GSI2PK = :current"
+				values[":current"] = current
+			}
+
+			_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:           aws.String(r.tableName),
+				Key:                 orderKey(order.OrderID),
+				UpdateExpression:    aws.String("SET GSI2PK = :gsi2pk, GSI2SK = :gsi2sk"),
+				ConditionExpression: aws.String(condition),
+				ExpressionAttributeNames: map[string]string{
+					"#status": "Status",
+				},
+				ExpressionAttributeValues: values,
+			})
+			if err != nil {
+				if isConditionalCheckFailed(err) {
+					continue
+				}
+				return updated, fmt.Errorf("failed to backfill order %d: %w", order.OrderID, err)
+			}
+			updated++
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return updated, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
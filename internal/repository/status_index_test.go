@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+func TestStatusIndexLegacyOrders(t *testing.T) {
+	repo := newTestDynamoRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	// putLegacy - GSI2 속성이 도입되기 전에 저장된 주문 (keep에 있는 GSI2 속성만 남김)
+	putLegacy := func(order *domain.Order, keep ...string) {
+		t.Helper()
+		av, err := orderItem(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		kept := map[string]bool{}
+		for _, name := range keep {
+			kept[name] = true
+		}
+		for _, name := range []string{"GSI2PK", "GSI2SK"} {
+			if !kept[name] {
+				delete(av, name)
+			}
+		}
+		if _, err := repo.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(repo.tableName),
+			Item:      av,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	listed := func(status domain.OrderStatus, id int) bool {
+		t.Helper()
+		page, err := repo.ListOrdersByStatus(ctx, StatusOrdersQuery{Status: status})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, o := range page.Orders {
+			if o.OrderID == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("status change writes missing GSI2SK", func(t *testing.T) {
+		putLegacy(testOrder(1, "user-1", now))
+		if err := repo.UpdateOrderStatus(ctx, 1, now, domain.OrderStatusPending, domain.OrderStatusConfirmed, now, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if !listed(domain.OrderStatusConfirmed, 1) {
+			t.Error("order 1 missing from CONFIRMED status index")
+		}
+	})
+
+	t.Run("backfill writes GSI2SK when GSI2PK is current", func(t *testing.T) {
+		order := testOrder(2, "user-1", now)
+		order.Status = domain.OrderStatusConfirmed
+		putLegacy(order, "GSI2PK")
+
+		n, err := repo.BackfillStatusIndex(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("backfilled %d orders, want 1", n)
+		}
+		if !listed(domain.OrderStatusConfirmed, 2) {
+			t.Error("order 2 missing from CONFIRMED status index")
+		}
+
+		out, err := repo.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(repo.tableName),
+			Key:       orderKey(2),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sk, _ := out.Item["GSI2SK"].(*types.AttributeValueMemberS)
+		if sk == nil || sk.Value != statusIndexSK(now, 2) {
+			t.Errorf("GSI2SK = %v, want %s", out.Item["GSI2SK"], statusIndexSK(now, 2))
+		}
+	})
+}
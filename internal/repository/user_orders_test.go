@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+type userOrderStore interface {
+	CreateOrder(ctx context.Context, order *domain.Order, idem *IdempotencyRecord, history *domain.HistoryEntry, saga *SagaWrite, outbox ...*OutboxMessage) error
+	GetOrdersByUser(ctx context.Context, q UserOrdersQuery) (*OrderPage, error)
+}
+
+func TestMemoryGetOrdersByUser(t *testing.T) {
+	testGetOrdersByUser(t, NewMemoryOrderRepository())
+}
+
+func TestDynamoGetOrdersByUser(t *testing.T) {
+	testGetOrdersByUser(t, newTestDynamoRepository(t))
+}
+
+func TestPostgresGetOrdersByUser(t *testing.T) {
+	testGetOrdersByUser(t, newTestPostgresRepository(t))
+}
+
+// testGetOrdersByUser - 같은 초에 생성된 주문의 경계 조건과 상태 필터 페이지
+func testGetOrdersByUser(t *testing.T, store userOrderStore) {
+	ctx := context.Background()
+	base := time.Now().UTC().Truncate(time.Second)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+
+	for id := 1; id <= 5; id++ {
+		order := testOrder(id, "user-1", at(id*200-100))
+		if id%2 == 1 {
+			order.Status = domain.OrderStatusConfirmed
+		}
+		if err := store.CreateOrder(ctx, order, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.CreateOrder(ctx, testOrder(6, "user-2", at(500)), nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// 경계는 초 단위가 아니라 정확한 생성 시각으로 비교 (양끝 포함)
+	page, err := store.GetOrdersByUser(ctx, UserOrdersQuery{UserID: "user-1", CreatedAfter: at(300), CreatedBefore: at(700)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := orderIDs(page.Orders); fmt.Sprint(got) != fmt.Sprint([]int{2, 3, 4}) {
+		t.Errorf("orders between bounds = %v, want [2 3 4]", got)
+	}
+	page, err = store.GetOrdersByUser(ctx, UserOrdersQuery{UserID: "user-1", CreatedAfter: at(301)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := orderIDs(page.Orders); fmt.Sprint(got) != fmt.Sprint([]int{3, 4, 5}) {
+		t.Errorf("orders after bound = %v, want [3 4 5]", got)
+	}
+
+	// 상태 필터를 써도 마지막 페이지 전까지는 Limit개를 채움
+	var got []*domain.Order
+	var startKey map[string]string
+	for pages := 1; ; pages++ {
+		page, err := store.GetOrdersByUser(ctx, UserOrdersQuery{UserID: "user-1", Status: domain.OrderStatusConfirmed, Limit: 2, StartKey: startKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, page.Orders...)
+		if page.NextKey == nil {
+			break
+		}
+		if len(page.Orders) != 2 {
+			t.Errorf("page %d has %d orders, want 2", pages, len(page.Orders))
+		}
+		if pages > 3 {
+			t.Fatal("too many pages")
+		}
+		startKey = page.NextKey
+	}
+	if ids := orderIDs(got); fmt.Sprint(ids) != fmt.Sprint([]int{1, 3, 5}) {
+		t.Errorf("confirmed orders = %v, want [1 3 5]", ids)
+	}
+}
+
+// orderIDs - 같은 초에 생성된 주문은 저장소마다 순서가 다를 수 있어 정렬해 비교
+func orderIDs(orders []*domain.Order) []int {
+	ids := make([]int, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.OrderID)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,248 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// 다른 처리(재고 결과 수신, 타임아웃)와 동시에 사가를 저장할 때의 재시도 횟수
+const maxCancelAttempts = 3
+
+// ErrAmendmentClosed - 재고 차감 결과를 이미 받기 시작한 주문
+var ErrAmendmentClosed = errors.New("order amendment closed: stock deduction already started")
+
+// CancelOrder - 주문을 취소하고 이미 차감된 상품마다 보상 이벤트를 발행
+// 상태 변경, 사가 중단, 보상 이벤트는 하나의 트랜잭션으로 기록하며 사가가 없는 주문은 주문 항목으로 보상을 계산
+// 이미 취소된 주문은 보상만 다시 시도
+func (o *Orchestrator) CancelOrder(ctx context.Context, orderID int, reason string) (*domain.Order, error) {
+	var order *domain.Order
+	err := retryOnConflict(func() error {
+		current, err := o.store.GetOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if current.Status == domain.OrderStatusCancelled {
+			order = current
+			return o.abort(ctx, orderID, reason)
+		}
+
+		saga, err := o.store.GetSaga(ctx, orderID)
+		if err != nil && !errors.Is(err, repository.ErrSagaNotFound) {
+			return err
+		}
+		var compensations int
+		// 상태 머신 검증과 조건부 상태 변경은 Transition이 담당
+		order, err = o.orders.Transition(ctx, orderID, domain.OrderStatusCancelled, reason, func(before, _ *domain.Order) (*repository.SagaWrite, []*repository.OutboxMessage, error) {
+			w, outbox, err := cancelPlan(saga, before, reason, time.Now(), o.cfg.StepTimeout)
+			compensations = len(outbox)
+			return w, outbox, err
+		})
+		if errors.Is(err, repository.ErrSagaExists) {
+			// 읽은 뒤 재고 결과가 도착해 사가가 생성됨 - 다시 읽어 계산
+			return repository.ErrSagaVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		o.logger.Info("Saga aborted by order cancellation",
+			zap.Int("order_id", orderID),
+			zap.Int("compensations", compensations),
+			zap.String("reason", reason))
+		return nil
+	})
+	if err != nil {
+		o.logger.Error("Failed to cancel order",
+			zap.Int("order_id", orderID),
+			zap.Error(err))
+		return nil, err
+	}
+	return order, nil
+}
+
+// cancelPlan - 취소와 같은 트랜잭션으로 기록할 중단된 사가와 보상 이벤트
+// 사가가 없는 주문은 주문 항목으로 사가를 만들고, PENDING이 아니었으면(재고 차감 완료) 모든 단계를 완료로 보고 보상
+func cancelPlan(saga *domain.Saga, before *domain.Order, reason string, now time.Time, timeout time.Duration) (*repository.SagaWrite, []*repository.OutboxMessage, error) {
+	w := sagaWrite(saga, before, now, timeout)
+	outbox, err := abortSaga(w.Saga, reason, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return w, outbox, nil
+}
+
+// sagaWrite - 읽은 사가의 사본을 버전 조건과 함께 저장하거나, 사가가 없으면 변경 전 주문으로 새로 만듦
+// 사가 없이 PENDING이 아닌 주문(재고 차감 완료)은 모든 단계를 완료로 봄
+func sagaWrite(saga *domain.Saga, before *domain.Order, now time.Time, timeout time.Duration) *repository.SagaWrite {
+	if saga != nil {
+		next := *saga
+		next.Steps = append([]domain.SagaStep(nil), saga.Steps...)
+		return &repository.SagaWrite{Saga: &next, ExpectedVersion: saga.Version}
+	}
+
+	w := &repository.SagaWrite{Saga: domain.NewSaga(before, now.Add(timeout), now), Create: true}
+	if before.Status != domain.OrderStatusPending {
+		for i := range w.Saga.Steps {
+			w.Saga.Steps[i].Status = domain.SagaStepCompleted
+		}
+	}
+	return w
+}
+
+// CancelLines - 주문 항목을 부분 취소하고 이미 차감된 수량만큼 보상 이벤트를 발행
+// 주문 변경, 사가 갱신, 보상 이벤트는 하나의 트랜잭션으로 기록
+// 아직 차감 결과가 없는 상품은 결과가 도착할 때 HandleStockResult에서 보상
+func (o *Orchestrator) CancelLines(ctx context.Context, orderID int, lines []domain.CancelLine, reason string) (*domain.Order, error) {
+	var order *domain.Order
+	err := retryOnConflict(func() error {
+		saga, err := o.store.GetSaga(ctx, orderID)
+		if err != nil && !errors.Is(err, repository.ErrSagaNotFound) {
+			return err
+		}
+		var compensations int
+		order, err = o.orders.CancelLines(ctx, orderID, lines, reason, func(before, after *domain.Order) (*repository.SagaWrite, []*repository.OutboxMessage, error) {
+			now := time.Now()
+			w := sagaWrite(saga, before, now, o.cfg.StepTimeout)
+			outbox, err := settleCancelled(w.Saga, after, reason, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			w.Saga.UpdatedAt = now
+			compensations = len(outbox)
+			return w, outbox, nil
+		})
+		if errors.Is(err, repository.ErrSagaExists) {
+			// 읽은 뒤 재고 결과가 도착해 사가가 생성됨 - 다시 읽어 계산
+			return repository.ErrSagaVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		if compensations > 0 {
+			o.logger.Info("Cancelled order lines compensated",
+				zap.Int("order_id", orderID),
+				zap.Int("compensations", compensations),
+				zap.String("reason", reason))
+		}
+		return nil
+	})
+	if err != nil {
+		o.logger.Error("Failed to cancel order lines",
+			zap.Int("order_id", orderID),
+			zap.Error(err))
+		return nil, err
+	}
+	return order, nil
+}
+
+// AmendOrder - 재고 차감 결과를 받기 전의 주문만 항목 변경 허용
+// 차감이 시작된 뒤에는 단계별 수량과 보상 수량을 맞출 수 없으므로 부분 취소/주문 취소를 사용해야 함
+// 단계는 변경 후 항목으로 다시 만들어 주문과 같은 트랜잭션으로 기록하므로,
+// 읽은 뒤 재고 결과가 먼저 반영되면 사가 버전 충돌로 변경이 기록되지 않음
+func (o *Orchestrator) AmendOrder(ctx context.Context, orderID, expectedVersion int, changes []domain.ItemChange, reason string) (*domain.Order, error) {
+	var order *domain.Order
+	err := retryOnConflict(func() error {
+		saga, err := o.store.GetSaga(ctx, orderID)
+		if err != nil && !errors.Is(err, repository.ErrSagaNotFound) {
+			return err
+		}
+		if err == nil && !saga.AwaitingResults() {
+			return ErrAmendmentClosed
+		}
+
+		order, err = o.orders.AmendOrder(ctx, orderID, expectedVersion, changes, reason, func(_, amended *domain.Order) (*repository.SagaWrite, []*repository.OutboxMessage, error) {
+			now := time.Now()
+			next := domain.NewSaga(amended, now.Add(o.cfg.StepTimeout), now)
+			if saga == nil {
+				// 사가를 주문과 함께 만들기 전에 생성된 주문 - 사가가 아직 없을 때만 변경
+				return &repository.SagaWrite{Saga: next, Create: true}, nil, nil
+			}
+			next.CreatedAt = saga.CreatedAt
+			return &repository.SagaWrite{Saga: next, ExpectedVersion: saga.Version}, nil, nil
+		})
+		if errors.Is(err, repository.ErrSagaExists) {
+			// 읽은 뒤 재고 결과가 도착해 사가가 생성됨 - 다시 읽어 판단
+			return repository.ErrSagaVersionConflict
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// retryOnConflict - 다른 처리와 동시에 사가를 저장해 버전이 충돌하면 다시 읽어 재시도
+func retryOnConflict(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < maxCancelAttempts; attempt++ {
+		if err = fn(); !errors.Is(err, repository.ErrSagaVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
+
+// abort - 취소된 주문의 사가를 중단하고 아직 보상하지 않은 완료 단계를 보상 (취소 후 보상 재시도)
+func (o *Orchestrator) abort(ctx context.Context, orderID int, reason string) error {
+	saga, err := o.load(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if saga.Status == domain.SagaStatusAborted && saga.OrderUpdated && !hasCompleted(saga) {
+		return nil
+	}
+
+	expected := saga.Version
+	outbox, err := abortSaga(saga, reason, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := o.store.SaveSaga(ctx, saga, expected, outbox...); err != nil {
+		return err
+	}
+	if len(outbox) > 0 {
+		o.notifier.Notify()
+	}
+
+	o.logger.Info("Saga aborted by order cancellation",
+		zap.Int("order_id", orderID),
+		zap.Int("compensations", len(outbox)),
+		zap.String("reason", reason))
+	return nil
+}
+
+// abortSaga - 사가를 중단(주문 반영 완료)으로 바꾸고 완료된 단계마다 보상 이벤트 생성
+// 아직 결과가 오지 않은 단계는 나중에 차감 완료가 도착하면 HandleStockResult에서 보상됨
+func abortSaga(saga *domain.Saga, reason string, now time.Time) ([]*repository.OutboxMessage, error) {
+	var outbox []*repository.OutboxMessage
+	for i := range saga.Steps {
+		step := &saga.Steps[i]
+		if step.Status != domain.SagaStepCompleted {
+			continue
+		}
+		msg, err := compensate(saga, step, reason, now)
+		if err != nil {
+			return nil, err
+		}
+		outbox = append(outbox, msg)
+	}
+
+	saga.Status = domain.SagaStatusAborted
+	saga.OrderUpdated = true
+	saga.UpdatedAt = now
+	return outbox, nil
+}
+
+func hasCompleted(saga *domain.Saga) bool {
+	for _, step := range saga.Steps {
+		if step.Status == domain.SagaStepCompleted {
+			return true
+		}
+	}
+	return false
+}
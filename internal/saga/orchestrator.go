@@ -0,0 +1,407 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/order-service/internal/statemachine"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Store - 사가 상태 저장소
+type Store interface {
+	GetOrder(ctx context.Context, id int) (*domain.Order, error)
+	CreateSaga(ctx context.Context, saga *domain.Saga) error
+	GetSaga(ctx context.Context, orderID int) (*domain.Saga, error)
+	SaveSaga(ctx context.Context, saga *domain.Saga, expectedVersion int, outbox ...*repository.OutboxMessage) error
+	ListDueSagas(ctx context.Context, now time.Time, limit int32) ([]*domain.Saga, error)
+}
+
+// OrderUpdater - 사가 결과와 취소/변경 요청을 주문에 반영
+type OrderUpdater interface {
+	Transition(ctx context.Context, id int, to domain.OrderStatus, reason string, plan repository.SagaPlan) (*domain.Order, error)
+	CancelLines(ctx context.Context, id int, lines []domain.CancelLine, reason string, plan repository.SagaPlan) (*domain.Order, error)
+	AmendOrder(ctx context.Context, id, expectedVersion int, changes []domain.ItemChange, reason string, plan repository.SagaPlan) (*domain.Order, error)
+}
+
+// Notifier - 아웃박스에 새 메시지가 기록되었음을 알림
+type Notifier interface {
+	Notify()
+}
+
+type Config struct {
+	// 사가 생성(주문 생성 또는 항목 변경) 후 모든 단계가 끝나야 하는 시간
+	StepTimeout  time.Duration
+	PollInterval time.Duration
+	BatchSize    int32
+}
+
+// Orchestrator - 주문 상품별 재고 차감 단계를 추적하고, 실패 시 완료된 단계를 보상
+type Orchestrator struct {
+	store    Store
+	orders   OrderUpdater
+	notifier Notifier
+	cfg      Config
+	logger   *zap.Logger
+}
+
+func NewOrchestrator(store Store, orders OrderUpdater, notifier Notifier, cfg Config, logger *zap.Logger) *Orchestrator {
+	if cfg.StepTimeout <= 0 {
+		cfg.StepTimeout = 5 * time.Minute
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 25
+	}
+	return &Orchestrator{
+		store:    store,
+		orders:   orders,
+		notifier: notifier,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// HandleStockResult - 재고 차감 결과를 해당 단계에 반영
+// ProductID가 없는 결과는 주문 전체에 대한 결과로 보고 모든 대기 단계에 적용
+func (o *Orchestrator) HandleStockResult(ctx context.Context, event events.StockResultEvent) error {
+	var status domain.SagaStepStatus
+	switch event.EventType {
+	case events.EventTypeStockDeducted:
+		status = domain.SagaStepCompleted
+	case events.EventTypeStockDeductionFailed:
+		status = domain.SagaStepFailed
+	default:
+		return fmt.Errorf("%w: unknown stock event type %q", events.ErrPermanent, event.EventType)
+	}
+
+	saga, err := o.load(ctx, event.OrderID)
+	if err != nil {
+		return err
+	}
+
+	if event.ProductID != "" && saga.Step(event.ProductID) == nil {
+		return fmt.Errorf("%w: product %s is not part of order %d", events.ErrPermanent, event.ProductID, event.OrderID)
+	}
+
+	expected := saga.Version
+	now := time.Now()
+	changed := false
+	var outbox []*repository.OutboxMessage
+
+	for i := range saga.Steps {
+		step := &saga.Steps[i]
+		if event.ProductID != "" && step.ProductID != event.ProductID {
+			continue
+		}
+
+		switch {
+		case step.Status == domain.SagaStepPending:
+			step.Status, step.Reason, step.UpdatedAt = status, event.Reason, now
+			changed = true
+		case step.Status == domain.SagaStepTimedOut && status == domain.SagaStepCompleted:
+			// 타임아웃 이후 늦게 도착한 차감 결과도 보상해야 함
+			step.Status, step.Reason, step.UpdatedAt = domain.SagaStepCompleted, event.Reason, now
+			changed = true
+		}
+
+		// 이미 중단된 사가에 뒤늦게 완료된 단계는 바로 보상
+		if saga.Status == domain.SagaStatusAborted && step.Status == domain.SagaStepCompleted {
+			msg, err := compensate(saga, step, "saga aborted", now)
+			if err != nil {
+				return err
+			}
+			outbox = append(outbox, msg)
+		}
+	}
+
+	// 차감 대기 중에 부분 취소된 수량은 차감이 끝난 지금 보상
+	if changed {
+		order, err := o.store.GetOrder(ctx, event.OrderID)
+		if err != nil {
+			return err
+		}
+		msgs, err := settleCancelled(saga, order, "order line cancelled", now)
+		if err != nil {
+			return err
+		}
+		outbox = append(outbox, msgs...)
+	}
+
+	if !changed {
+		o.logger.Info("Ignoring duplicate stock result",
+			zap.String("event_id", event.EventID),
+			zap.Int("order_id", event.OrderID),
+			zap.String("product_id", event.ProductID))
+		// 이전 처리에서 주문 반영만 실패했을 수 있음
+		return o.finalize(ctx, saga)
+	}
+
+	msgs, err := o.advance(saga, now)
+	if err != nil {
+		return err
+	}
+	outbox = append(outbox, msgs...)
+
+	saga.UpdatedAt = now
+	if err := o.store.SaveSaga(ctx, saga, expected, outbox...); err != nil {
+		return err
+	}
+	if len(outbox) > 0 {
+		o.notifier.Notify()
+	}
+
+	return o.finalize(ctx, saga)
+}
+
+// Run - 타임아웃된 단계와 주문 반영이 끝나지 않은 사가를 주기적으로 처리 (재시작 후 재개)
+func (o *Orchestrator) Run(ctx context.Context) {
+	o.logger.Info("Saga orchestrator started",
+		zap.Duration("step_timeout", o.cfg.StepTimeout),
+		zap.Duration("poll_interval", o.cfg.PollInterval))
+
+	ticker := time.NewTicker(o.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		o.resumeDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			o.logger.Info("Saga orchestrator stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *Orchestrator) resumeDue(ctx context.Context) {
+	sagas, err := o.store.ListDueSagas(ctx, time.Now(), o.cfg.BatchSize)
+	if err != nil {
+		o.logger.Error("Failed to list due sagas", zap.Error(err))
+		return
+	}
+
+	for _, saga := range sagas {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := o.resume(ctx, saga); err != nil {
+			o.logger.Error("Failed to resume saga",
+				zap.Int("order_id", saga.OrderID),
+				zap.Error(err))
+		}
+	}
+}
+
+func (o *Orchestrator) resume(ctx context.Context, saga *domain.Saga) error {
+	now := time.Now()
+	if saga.Status != domain.SagaStatusRunning || now.Before(saga.Deadline) {
+		return o.finalize(ctx, saga)
+	}
+
+	expected := saga.Version
+	for i := range saga.Steps {
+		step := &saga.Steps[i]
+		if step.Status == domain.SagaStepPending {
+			step.Status, step.Reason, step.UpdatedAt = domain.SagaStepTimedOut, "step timed out", now
+		}
+	}
+
+	outbox, err := o.advance(saga, now)
+	if err != nil {
+		return err
+	}
+
+	saga.UpdatedAt = now
+	if err := o.store.SaveSaga(ctx, saga, expected, outbox...); err != nil {
+		return err
+	}
+	if len(outbox) > 0 {
+		o.notifier.Notify()
+	}
+
+	o.logger.Warn("Saga steps timed out",
+		zap.Int("order_id", saga.OrderID),
+		zap.String("saga_status", string(saga.Status)))
+
+	return o.finalize(ctx, saga)
+}
+
+// advance - 단계 상태로 사가 상태를 결정하고, 중단 시 완료된 단계의 보상 이벤트 생성
+func (o *Orchestrator) advance(saga *domain.Saga, now time.Time) ([]*repository.OutboxMessage, error) {
+	if saga.Status != domain.SagaStatusRunning {
+		return nil, nil
+	}
+
+	completed := 0
+	failed := false
+	for _, step := range saga.Steps {
+		switch step.Status {
+		case domain.SagaStepCompleted, domain.SagaStepCompensated:
+			// 진행 중인 사가의 COMPENSATED 단계는 부분 취소로 전량 보상된 단계
+			completed++
+		case domain.SagaStepFailed, domain.SagaStepTimedOut:
+			failed = true
+		}
+	}
+
+	switch {
+	case failed:
+		saga.Status = domain.SagaStatusAborted
+		var outbox []*repository.OutboxMessage
+		for i := range saga.Steps {
+			step := &saga.Steps[i]
+			if step.Status != domain.SagaStepCompleted {
+				continue
+			}
+			msg, err := compensate(saga, step, "saga aborted", now)
+			if err != nil {
+				return nil, err
+			}
+			outbox = append(outbox, msg)
+		}
+		return outbox, nil
+	case completed == len(saga.Steps):
+		saga.Status = domain.SagaStatusCompleted
+	}
+	return nil, nil
+}
+
+// finalize - 종료된 사가의 결과를 주문 상태에 반영
+func (o *Orchestrator) finalize(ctx context.Context, saga *domain.Saga) error {
+	if saga.Status == domain.SagaStatusRunning || saga.OrderUpdated {
+		return nil
+	}
+
+	to, reason := domain.OrderStatusConfirmed, "all stock deductions completed"
+	if saga.Status == domain.SagaStatusAborted {
+		to, reason = domain.OrderStatusCancelled, abortReason(saga)
+	}
+
+	_, err := o.orders.Transition(ctx, saga.OrderID, to, reason, nil)
+	if err != nil && !errors.Is(err, statemachine.ErrInvalidTransition) {
+		return err
+	}
+	if err != nil {
+		// 이미 다른 경로로 상태가 바뀐 주문
+		o.logger.Info("Order already moved past saga result",
+			zap.Int("order_id", saga.OrderID),
+			zap.String("saga_status", string(saga.Status)),
+			zap.Error(err))
+	}
+
+	expected := saga.Version
+	saga.OrderUpdated = true
+	saga.UpdatedAt = time.Now()
+	if err := o.store.SaveSaga(ctx, saga, expected); err != nil {
+		saga.OrderUpdated = false
+		return err
+	}
+	return nil
+}
+
+// load - 사가가 없으면(사가를 주문과 함께 만들기 전에 생성된 주문) 주문 항목으로 새로 생성
+func (o *Orchestrator) load(ctx context.Context, orderID int) (*domain.Saga, error) {
+	saga, err := o.store.GetSaga(ctx, orderID)
+	if err == nil {
+		return saga, nil
+	}
+	if !errors.Is(err, repository.ErrSagaNotFound) {
+		return nil, err
+	}
+
+	order, err := o.store.GetOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, fmt.Errorf("%w: order %d not found", events.ErrPermanent, orderID)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	saga = domain.NewSaga(order, now.Add(o.cfg.StepTimeout), now)
+	if err := o.store.CreateSaga(ctx, saga); err != nil {
+		if errors.Is(err, repository.ErrSagaExists) {
+			return o.store.GetSaga(ctx, orderID)
+		}
+		return nil, err
+	}
+	return saga, nil
+}
+
+// compensate - 아직 보상하지 않은 차감 수량 전체를 보상
+func compensate(saga *domain.Saga, step *domain.SagaStep, reason string, now time.Time) (*repository.OutboxMessage, error) {
+	msg, err := compensationMessage(saga, step, step.Quantity-step.CompensatedQuantity, reason, now)
+	if err != nil {
+		return nil, err
+	}
+	step.CompensatedQuantity = step.Quantity
+	step.Status, step.UpdatedAt = domain.SagaStepCompensated, now
+	return msg, nil
+}
+
+// settleCancelled - 주문의 부분 취소 수량을 단계에 반영하고, 차감이 끝난 단계는 취소 수량만큼 보상
+func settleCancelled(saga *domain.Saga, order *domain.Order, reason string, now time.Time) ([]*repository.OutboxMessage, error) {
+	cancelled := make(map[string]int)
+	for _, item := range order.Items {
+		cancelled[item.ProductID] += item.CancelledQuantity
+	}
+
+	var outbox []*repository.OutboxMessage
+	for i := range saga.Steps {
+		step := &saga.Steps[i]
+		if cancelled[step.ProductID] > step.CancelledQuantity {
+			step.CancelledQuantity = cancelled[step.ProductID]
+			step.UpdatedAt = now
+		}
+		if step.Status != domain.SagaStepCompleted || saga.Status == domain.SagaStatusAborted ||
+			step.CancelledQuantity <= step.CompensatedQuantity {
+			continue
+		}
+
+		msg, err := compensationMessage(saga, step, step.CancelledQuantity-step.CompensatedQuantity, reason, now)
+		if err != nil {
+			return nil, err
+		}
+		outbox = append(outbox, msg)
+		step.CompensatedQuantity = step.CancelledQuantity
+		if step.CompensatedQuantity >= step.Quantity {
+			step.Status = domain.SagaStepCompensated
+		}
+	}
+	return outbox, nil
+}
+
+func compensationMessage(saga *domain.Saga, step *domain.SagaStep, quantity int, reason string, now time.Time) (*repository.OutboxMessage, error) {
+	event := events.CompensationEvent{
+		EventID:   uuid.New().String(),
+		OrderID:   saga.OrderID,
+		ProductID: step.ProductID,
+		Quantity:  quantity,
+		Reason:    reason,
+		Timestamp: now,
+	}
+	return repository.NewOutboxMessage(events.TopicCompensationEvents, events.EventTypeCompensation, strconv.Itoa(event.OrderID), event)
+}
+
+func abortReason(saga *domain.Saga) string {
+	for _, step := range saga.Steps {
+		switch step.Status {
+		case domain.SagaStepFailed:
+			return fmt.Sprintf("stock deduction failed for product %s", step.ProductID)
+		case domain.SagaStepTimedOut:
+			return fmt.Sprintf("stock deduction timed out for product %s", step.ProductID)
+		}
+	}
+	return "saga aborted"
+}
@@ -0,0 +1,386 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/catalog"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/cloud-wave-best-zizon/order-service/internal/idgen"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/order-service/internal/service"
+	"go.uber.org/zap"
+)
+
+var testPrice = domain.Money{Amount: 1000, Currency: "KRW"}
+
+// fixedCatalog - 모든 상품이 testPrice인 가격표
+type fixedCatalog struct {
+	version string
+}
+
+func (c *fixedCatalog) GetPrices(ctx context.Context, productIDs []string) (*catalog.Quote, error) {
+	quote := &catalog.Quote{Version: c.version, Prices: make(map[string]catalog.Price)}
+	for _, id := range productIDs {
+		quote.Prices[id] = catalog.Price{ProductID: id, Name: id, Price: testPrice, Version: c.version}
+	}
+	return quote, nil
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify() {}
+
+// hookedOrders - 사가와 함께 주문 변경을 기록하기 직전에 hook을 한 번 실행하는 OrderUpdater
+type hookedOrders struct {
+	*service.OrderService
+	hook func()
+}
+
+func (h *hookedOrders) runHook() {
+	if h.hook != nil {
+		hook := h.hook
+		h.hook = nil
+		hook()
+	}
+}
+
+func (h *hookedOrders) Transition(ctx context.Context, id int, to domain.OrderStatus, reason string, plan repository.SagaPlan) (*domain.Order, error) {
+	if plan != nil {
+		h.runHook()
+	}
+	return h.OrderService.Transition(ctx, id, to, reason, plan)
+}
+
+func (h *hookedOrders) AmendOrder(ctx context.Context, id, expectedVersion int, changes []domain.ItemChange, reason string, plan repository.SagaPlan) (*domain.Order, error) {
+	h.runHook()
+	return h.OrderService.AmendOrder(ctx, id, expectedVersion, changes, reason, plan)
+}
+
+func (h *hookedOrders) CancelLines(ctx context.Context, id int, lines []domain.CancelLine, reason string, plan repository.SagaPlan) (*domain.Order, error) {
+	h.runHook()
+	return h.OrderService.CancelLines(ctx, id, lines, reason, plan)
+}
+
+type fixture struct {
+	catalog      *fixedCatalog
+	store        *repository.MemoryOrderRepository
+	orders       *service.OrderService
+	updater      *hookedOrders
+	orchestrator *Orchestrator
+}
+
+func newFixture(t *testing.T, timeout time.Duration) *fixture {
+	t.Helper()
+	ids, err := idgen.NewSnowflake(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prices := &fixedCatalog{version: "v1"}
+	store := repository.NewMemoryOrderRepository()
+	orders := service.NewOrderService(store, nopNotifier{}, ids, prices, service.Config{
+		IdempotencyTTL:  time.Hour,
+		SagaStepTimeout: timeout,
+	}, zap.NewNop())
+	updater := &hookedOrders{OrderService: orders}
+	return &fixture{
+		catalog:      prices,
+		store:        store,
+		orders:       orders,
+		updater:      updater,
+		orchestrator: NewOrchestrator(store, updater, nopNotifier{}, Config{StepTimeout: timeout}, zap.NewNop()),
+	}
+}
+
+func (f *fixture) createOrder(t *testing.T) int {
+	t.Helper()
+	result, err := f.orders.CreateOrder(context.Background(), domain.CreateOrderRequest{
+		UserID: "user-1",
+		Items: []domain.OrderItem{
+			{ProductID: "PROD-A", Quantity: 2, Price: testPrice},
+			{ProductID: "PROD-B", Quantity: 1, Price: testPrice},
+		},
+		IdempotencyKey: "key-" + time.Now().Format(time.RFC3339Nano),
+	}, "req-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.Response.OrderID
+}
+
+func (f *fixture) deducted(t *testing.T, orderID int, productID string) {
+	t.Helper()
+	err := f.orchestrator.HandleStockResult(context.Background(), events.StockResultEvent{
+		EventID:   "stock-" + productID,
+		EventType: events.EventTypeStockDeducted,
+		OrderID:   orderID,
+		ProductID: productID,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOrderWithoutStockResultsTimesOut(t *testing.T) {
+	f := newFixture(t, 20*time.Millisecond)
+	ctx := context.Background()
+	orderID := f.createOrder(t)
+
+	saga, err := f.store.GetSaga(ctx, orderID)
+	if err != nil {
+		t.Fatalf("saga not created with order: %v", err)
+	}
+	if !saga.AwaitingResults() || len(saga.Steps) != 2 {
+		t.Fatalf("saga = %+v, want two pending steps", saga)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	f.orchestrator.resumeDue(ctx)
+
+	order, err := f.store.GetOrder(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != domain.OrderStatusCancelled {
+		t.Errorf("status = %s, want CANCELLED after saga timeout", order.Status)
+	}
+}
+
+func TestAmendOrderRebuildsPendingSaga(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+	orderID := f.createOrder(t)
+
+	order, err := f.orchestrator.AmendOrder(ctx, orderID, 1, []domain.ItemChange{
+		{Op: domain.LineActionChange, LineID: 1, Quantity: 5},
+		{Op: domain.LineActionAdd, ProductID: "PROD-C", Quantity: 1, Price: testPrice},
+	}, "customer request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Version != 2 {
+		t.Errorf("version = %d, want 2", order.Version)
+	}
+
+	saga, err := f.store.GetSaga(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"PROD-A": 5, "PROD-B": 1, "PROD-C": 1}
+	if len(saga.Steps) != len(want) {
+		t.Fatalf("steps = %+v, want %v", saga.Steps, want)
+	}
+	for _, step := range saga.Steps {
+		if step.Quantity != want[step.ProductID] || step.Status != domain.SagaStepPending {
+			t.Errorf("step %+v, want quantity %d pending", step, want[step.ProductID])
+		}
+	}
+}
+
+func TestAmendOrderRecordsCatalogVersion(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+	orderID := f.createOrder(t)
+
+	// 수량 변경만 하면 생성 시 카탈로그 버전 유지
+	order, err := f.orchestrator.AmendOrder(ctx, orderID, 1, []domain.ItemChange{
+		{Op: domain.LineActionChange, LineID: 1, Quantity: 3},
+	}, "customer request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.CatalogVersion != "v1" {
+		t.Errorf("catalog version = %q after quantity change, want v1", order.CatalogVersion)
+	}
+
+	// 항목을 추가하면 새로 단가를 산정한 버전을 기록
+	f.catalog.version = "v2"
+	if _, err := f.orchestrator.AmendOrder(ctx, orderID, 2, []domain.ItemChange{
+		{Op: domain.LineActionAdd, ProductID: "PROD-C", Quantity: 1, Price: testPrice},
+	}, "customer request"); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := f.store.GetOrder(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.CatalogVersion != "v2" {
+		t.Errorf("stored catalog version = %q, want v2", stored.CatalogVersion)
+	}
+}
+
+func TestAmendOrderClosedByConcurrentStockResult(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+	orderID := f.createOrder(t)
+
+	// 사가를 확인한 뒤 변경을 기록하기 전에 재고 결과가 먼저 반영됨
+	f.updater.hook = func() { f.deducted(t, orderID, "PROD-A") }
+
+	_, err := f.orchestrator.AmendOrder(ctx, orderID, 1, []domain.ItemChange{
+		{Op: domain.LineActionChange, LineID: 1, Quantity: 5},
+	}, "customer request")
+	if !errors.Is(err, ErrAmendmentClosed) {
+		t.Fatalf("err = %v, want ErrAmendmentClosed", err)
+	}
+
+	order, err := f.store.GetOrder(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Version != 1 || order.Items[0].Quantity != 2 {
+		t.Errorf("order changed despite closed amendment: version %d, quantity %d", order.Version, order.Items[0].Quantity)
+	}
+	saga, err := f.store.GetSaga(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step := saga.Step("PROD-A"); step.Quantity != 2 || step.Status != domain.SagaStepCompleted {
+		t.Errorf("PROD-A step = %+v, want quantity 2 completed", step)
+	}
+}
+
+// compensations - 발행 대기 중인 보상 이벤트의 상품별 수량
+func (f *fixture) compensations(t *testing.T) map[string]int {
+	t.Helper()
+	msgs, err := f.store.ListPendingOutbox(context.Background(), time.Now(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]int)
+	for _, msg := range msgs {
+		if msg.Topic != events.TopicCompensationEvents {
+			continue
+		}
+		var ce events.CloudEvent
+		if err := json.Unmarshal(msg.Payload, &ce); err != nil {
+			t.Fatal(err)
+		}
+		var event events.CompensationEvent
+		if err := json.Unmarshal(ce.Data, &event); err != nil {
+			t.Fatal(err)
+		}
+		got[event.ProductID] += event.Quantity
+	}
+	return got
+}
+
+func TestCancelConfirmedOrderWithoutSagaCompensatesItems(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+
+	// 사가를 주문과 함께 만들기 전에 확정된 주문
+	now := time.Now()
+	legacy := &domain.Order{
+		OrderID: 42,
+		UserID:  "user-1",
+		Items: []domain.OrderItem{
+			{LineID: 1, ProductID: "PROD-A", Quantity: 2, Price: testPrice, Status: domain.LineStatusActive},
+			{LineID: 2, ProductID: "PROD-B", Quantity: 1, Price: testPrice, Status: domain.LineStatusActive},
+			{LineID: 3, ProductID: "PROD-A", Quantity: 1, Price: testPrice, Status: domain.LineStatusActive},
+		},
+		Status:    domain.OrderStatusConfirmed,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   2,
+	}
+	if err := f.store.CreateOrder(ctx, legacy, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := f.orchestrator.CancelOrder(ctx, 42, "customer request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != domain.OrderStatusCancelled {
+		t.Errorf("status = %s, want CANCELLED", order.Status)
+	}
+	if got, want := f.compensations(t), map[string]int{"PROD-A": 3, "PROD-B": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("compensations = %v, want %v", got, want)
+	}
+
+	saga, err := f.store.GetSaga(ctx, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saga.Status != domain.SagaStatusAborted || !saga.OrderUpdated {
+		t.Errorf("saga = %+v, want aborted and applied", saga)
+	}
+
+	// 같은 요청을 다시 보내도 보상은 한 번만
+	if _, err := f.orchestrator.CancelOrder(ctx, 42, "customer request"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f.compensations(t), map[string]int{"PROD-A": 3, "PROD-B": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("compensations after retry = %v, want %v", got, want)
+	}
+}
+
+func TestCancelOrderCompensatesStepCompletedDuringCancel(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+	orderID := f.createOrder(t)
+	f.deducted(t, orderID, "PROD-B")
+
+	// 사가를 읽은 뒤 취소를 기록하기 전에 다른 상품의 차감이 완료됨
+	f.updater.hook = func() { f.deducted(t, orderID, "PROD-A") }
+
+	order, err := f.orchestrator.CancelOrder(ctx, orderID, "customer request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != domain.OrderStatusCancelled {
+		t.Errorf("status = %s, want CANCELLED", order.Status)
+	}
+	if got, want := f.compensations(t), map[string]int{"PROD-A": 2, "PROD-B": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("compensations = %v, want %v", got, want)
+	}
+}
+
+func TestCancelLinesRetriesWholeChangeOnSagaConflict(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	ctx := context.Background()
+	orderID := f.createOrder(t)
+	f.deducted(t, orderID, "PROD-A")
+
+	before, err := f.store.GetOrder(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := before.Items[0]
+
+	// 사가를 읽은 뒤 부분 취소를 기록하기 전에 다른 상품의 차감이 완료됨
+	f.updater.hook = func() { f.deducted(t, orderID, "PROD-B") }
+
+	order, err := f.orchestrator.CancelLines(ctx, orderID, []domain.CancelLine{{LineID: line.LineID, Quantity: 1}}, "customer request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 충돌한 시도의 주문 변경은 기록되지 않고 재시도에서 한 번만 반영됨
+	if len(order.LineHistory) != 1 {
+		t.Errorf("line history = %+v, want one change", order.LineHistory)
+	}
+	if got := order.Line(line.LineID).CancelledQuantity; got != 1 {
+		t.Errorf("cancelled quantity = %d, want 1", got)
+	}
+	if got, want := f.compensations(t), map[string]int{"PROD-A": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("compensations = %v, want %v", got, want)
+	}
+
+	saga, err := f.store.GetSaga(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step := saga.Step("PROD-A"); step.CancelledQuantity != 1 || step.CompensatedQuantity != 1 {
+		t.Errorf("PROD-A step = %+v, want 1 cancelled and compensated", step)
+	}
+	if step := saga.Step("PROD-B"); step.Status != domain.SagaStepCompleted {
+		t.Errorf("PROD-B step = %+v, want completed", step)
+	}
+}
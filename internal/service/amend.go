@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrVersionMismatch - If-Match로 받은 버전이 현재 주문 버전과 다름
+	ErrVersionMismatch = errors.New("order version does not match")
+	ErrNotAmendable    = errors.New("order can no longer be amended")
+	ErrInvalidChange   = errors.New("invalid item change")
+)
+
+// AmendOrder - PENDING 주문의 항목을 추가/변경/삭제하고 상품별 재고 변화량을 OrderAmendedEvent로 발행
+// expectedVersion이 현재 버전과 다르거나 저장 직전에 다른 변경이 있으면 실패
+// plan이 있으면 변경 후 주문으로 만든 사가를 주문과 같은 트랜잭션으로 기록
+func (s *OrderService) AmendOrder(ctx context.Context, id, expectedVersion int, changes []domain.ItemChange, reason string, plan repository.SagaPlan) (*domain.Order, error) {
+	order, err := s.orderRepo.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Version != expectedVersion {
+		return nil, fmt.Errorf("%w: current version is %d", ErrVersionMismatch, order.Version)
+	}
+	if order.Status != domain.OrderStatusPending {
+		return nil, fmt.Errorf("%w: order is %s", ErrNotAmendable, order.Status)
+	}
+
+	original := cloneOrder(order)
+	order.EnsureLineIDs()
+	before := activeQuantities(order)
+	now := time.Now()
+
+	var added []domain.OrderItem
+	var history []domain.LineChange
+	for _, ch := range changes {
+		switch ch.Op {
+		case domain.LineActionAdd:
+			if ch.ProductID == "" || ch.Quantity < 1 {
+				return nil, fmt.Errorf("%w: add requires product_id and a positive quantity", ErrInvalidChange)
+			}
+			added = append(added, domain.OrderItem{ProductID: ch.ProductID, Quantity: ch.Quantity, Price: ch.Price})
+
+		case domain.LineActionChange, domain.LineActionRemove:
+			item := order.Line(ch.LineID)
+			if item == nil {
+				return nil, fmt.Errorf("%w: %d", ErrLineNotFound, ch.LineID)
+			}
+			// 부분 취소된 항목은 재고 보상과 얽혀 있으므로 변경 대상에서 제외
+			if item.Status != domain.LineStatusActive {
+				return nil, fmt.Errorf("%w: line %d is %s", ErrInvalidChange, ch.LineID, item.Status)
+			}
+
+			entry := domain.LineChange{
+				LineID:       item.LineID,
+				ProductID:    item.ProductID,
+				Action:       ch.Op,
+				FromQuantity: item.Quantity,
+				FromStatus:   item.Status,
+				Reason:       reason,
+				ChangedAt:    now,
+			}
+			if ch.Op == domain.LineActionChange {
+				if ch.Quantity < 1 {
+					return nil, fmt.Errorf("%w: change requires a positive quantity, use remove instead", ErrInvalidChange)
+				}
+				item.Quantity = ch.Quantity
+			} else {
+				// 항목 ID가 재사용되지 않도록 삭제된 항목도 수량 0, CANCELLED로 남김
+				// 재고는 OrderAmendedEvent의 변화량으로 복구되므로 부분 취소(CancelledQuantity)와 구분
+				item.Quantity = 0
+				item.Status = domain.LineStatusCancelled
+			}
+			entry.ToQuantity = item.ActiveQuantity()
+			entry.ToStatus = item.Status
+			history = append(history, entry)
+
+		default:
+			return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidChange, ch.Op)
+		}
+	}
+
+	if len(added) > 0 {
+		priced, catalogVersion, err := s.priceItems(ctx, added)
+		if err != nil {
+			return nil, err
+		}
+		// 주문 생성과 같이 마지막으로 단가를 산정한 카탈로그 버전을 기록
+		order.CatalogVersion = catalogVersion
+		for _, item := range priced {
+			item.LineID = len(order.Items) + 1
+			order.Items = append(order.Items, item)
+			history = append(history, domain.LineChange{
+				LineID:     item.LineID,
+				ProductID:  item.ProductID,
+				Action:     domain.LineActionAdd,
+				ToQuantity: item.Quantity,
+				ToStatus:   item.Status,
+				Reason:     reason,
+				ChangedAt:  now,
+			})
+		}
+	}
+
+	deltas := stockDeltas(before, activeQuantities(order))
+	if len(deltas) == 0 {
+		return nil, fmt.Errorf("%w: changes leave the order unchanged", ErrInvalidChange)
+	}
+	remaining := 0
+	for _, item := range order.Items {
+		remaining += item.ActiveQuantity()
+	}
+	if remaining == 0 {
+		return nil, ErrCancelsWholeOrder
+	}
+
+	if err := order.Recalculate(); err != nil {
+		return nil, err
+	}
+	order.LineHistory = append(order.LineHistory, history...)
+	order.UpdatedAt = now
+
+	event := events.OrderAmendedEvent{
+		EventID:        uuid.New().String(),
+		OrderID:        order.OrderID,
+		UserID:         order.UserID,
+		Version:        expectedVersion + 1,
+		Items:          order.Items,
+		StockDeltas:    deltas,
+		TotalAmount:    order.TotalAmount,
+		CatalogVersion: order.CatalogVersion,
+		Reason:         reason,
+		Timestamp:      now,
+	}
+	msg, err := repository.NewOutboxMessage(events.TopicOrderEvents, events.EventTypeOrderAmended, strconv.Itoa(event.OrderID), event)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := newHistoryEntry(ctx, domain.HistoryActionAmended, original, order, expectedVersion+1, reason, now)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox := []*repository.OutboxMessage{msg}
+	var saga *repository.SagaWrite
+	if plan != nil {
+		var extra []*repository.OutboxMessage
+		if saga, extra, err = plan(original, order); err != nil {
+			return nil, err
+		}
+		outbox = append(outbox, extra...)
+	}
+
+	if err := s.orderRepo.UpdateOrder(ctx, order, expectedVersion, entry, saga, outbox...); err != nil {
+		s.logger.Warn("Order amendment failed",
+			zap.Int("order_id", id),
+			zap.Int("expected_version", expectedVersion),
+			zap.Error(err))
+		return nil, err
+	}
+	s.relay.Notify()
+
+	s.logger.Info("Order amended",
+		zap.Int("order_id", id),
+		zap.Int("version", order.Version),
+		zap.Int("changes", len(history)),
+		zap.Stringer("total_amount", order.TotalAmount))
+
+	return order, nil
+}
+
+// activeQuantities - 상품별 남은 주문 수량
+func activeQuantities(order *domain.Order) map[string]int {
+	qty := make(map[string]int)
+	for _, item := range order.Items {
+		qty[item.ProductID] += item.ActiveQuantity()
+	}
+	return qty
+}
+
+// stockDeltas - 상품별 수량 변화 (양수: 추가 차감, 음수: 복구), 변화 없는 상품은 제외
+func stockDeltas(before, after map[string]int) []events.StockDelta {
+	ids := make(map[string]struct{}, len(before)+len(after))
+	for id := range before {
+		ids[id] = struct{}{}
+	}
+	for id := range after {
+		ids[id] = struct{}{}
+	}
+
+	deltas := make([]events.StockDelta, 0, len(ids))
+	for id := range ids {
+		if d := after[id] - before[id]; d != 0 {
+			deltas = append(deltas, events.StockDelta{ProductID: id, Quantity: d})
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].ProductID < deltas[j].ProductID })
+	return deltas
+}
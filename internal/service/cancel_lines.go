@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/order-service/internal/statemachine"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrLineNotFound        = errors.New("order line not found")
+	ErrInvalidLineQuantity = errors.New("cancel quantity exceeds remaining quantity")
+	// 남은 항목을 모두 취소하려면 주문 취소 API를 사용해야 함
+	ErrCancelsWholeOrder = errors.New("change would cancel every line of the order")
+)
+
+// CancelLines - 주문 항목별로 지정한 수량을 취소하고 총액을 다시 계산
+// 재고 보상은 호출자(사가 오케스트레이터)가 plan에서 주문의 CancelledQuantity를 기준으로 계산하고,
+// 그 사가와 보상 이벤트는 주문 변경과 같은 트랜잭션으로 기록
+func (s *OrderService) CancelLines(ctx context.Context, id int, lines []domain.CancelLine, reason string, plan repository.SagaPlan) (*domain.Order, error) {
+	order, err := s.orderRepo.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 부분 취소는 주문 전체를 취소할 수 있는 상태에서만 허용
+	if !statemachine.CanTransition(order.Status, domain.OrderStatusCancelled) {
+		return nil, fmt.Errorf("%w: cannot cancel lines of %s order", statemachine.ErrInvalidTransition, order.Status)
+	}
+
+	expected := order.Version
+	original := cloneOrder(order)
+	order.EnsureLineIDs()
+
+	requested := make(map[int]int, len(lines))
+	var lineIDs []int
+	for _, l := range lines {
+		if _, ok := requested[l.LineID]; !ok {
+			lineIDs = append(lineIDs, l.LineID)
+		}
+		requested[l.LineID] += l.Quantity
+	}
+
+	now := time.Now()
+	changes := make([]domain.LineChange, 0, len(lineIDs))
+	for _, lineID := range lineIDs {
+		item := order.Line(lineID)
+		if item == nil {
+			return nil, fmt.Errorf("%w: %d", ErrLineNotFound, lineID)
+		}
+		qty := requested[lineID]
+		if qty > item.ActiveQuantity() {
+			return nil, fmt.Errorf("%w: line %d has %d remaining, requested %d",
+				ErrInvalidLineQuantity, lineID, item.ActiveQuantity(), qty)
+		}
+
+		from := item.Status
+		item.CancelledQuantity += qty
+		item.Status = domain.LineStatusPartiallyCancelled
+		if item.ActiveQuantity() == 0 {
+			item.Status = domain.LineStatusCancelled
+		}
+		changes = append(changes, domain.LineChange{
+			LineID:     lineID,
+			ProductID:  item.ProductID,
+			Action:     domain.LineActionCancel,
+			Quantity:   qty,
+			FromStatus: from,
+			ToStatus:   item.Status,
+			Reason:     reason,
+			ChangedAt:  now,
+		})
+	}
+
+	remaining := 0
+	for _, item := range order.Items {
+		remaining += item.ActiveQuantity()
+	}
+	if remaining == 0 {
+		return nil, ErrCancelsWholeOrder
+	}
+
+	if err := order.Recalculate(); err != nil {
+		return nil, err
+	}
+	order.LineHistory = append(order.LineHistory, changes...)
+	order.UpdatedAt = now
+
+	event := events.OrderLinesCancelledEvent{
+		EventID:     uuid.New().String(),
+		OrderID:     order.OrderID,
+		UserID:      order.UserID,
+		Lines:       changes,
+		TotalAmount: order.TotalAmount,
+		Reason:      reason,
+		Timestamp:   now,
+	}
+	msg, err := repository.NewOutboxMessage(events.TopicOrderEvents, events.EventTypeOrderLinesCancelled, strconv.Itoa(event.OrderID), event)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := newHistoryEntry(ctx, domain.HistoryActionLinesCancelled, original, order, expected+1, reason, now)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox := []*repository.OutboxMessage{msg}
+	var saga *repository.SagaWrite
+	if plan != nil {
+		var extra []*repository.OutboxMessage
+		if saga, extra, err = plan(original, order); err != nil {
+			return nil, err
+		}
+		outbox = append(outbox, extra...)
+	}
+
+	if err := s.orderRepo.UpdateOrder(ctx, order, expected, entry, saga, outbox...); err != nil {
+		s.logger.Warn("Order line cancellation failed",
+			zap.Int("order_id", id),
+			zap.Error(err))
+		return nil, err
+	}
+	s.relay.Notify()
+
+	s.logger.Info("Order lines cancelled",
+		zap.Int("order_id", id),
+		zap.Int("lines", len(changes)),
+		zap.Stringer("total_amount", order.TotalAmount),
+		zap.String("reason", reason))
+
+	return order, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// 내부 워커(사가 타임아웃, 재고 결과 처리 등)가 변경한 경우의 Actor ID
+const systemActorID = "system"
+
+type actorKey struct{}
+
+// WithActor - 주문 변경 이력에 남길 요청 주체를 context에 저장
+func WithActor(ctx context.Context, actor domain.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom - context의 요청 주체, 없으면 system
+func ActorFrom(ctx context.Context) domain.Actor {
+	if actor, ok := ctx.Value(actorKey{}).(domain.Actor); ok && actor.ID != "" {
+		return actor
+	}
+	return domain.Actor{ID: systemActorID}
+}
+
+// 매 변경마다 바뀌거나 별도로 기록되는 필드는 diff에서 제외
+var historyIgnoredFields = map[string]bool{
+	"updated_at":   true,
+	"version":      true,
+	"line_history": true,
+}
+
+// newHistoryEntry - before/after 주문을 비교한 변경 이력 (생성 시 before는 nil)
+func newHistoryEntry(ctx context.Context, action domain.HistoryAction, before, after *domain.Order, version int, reason string, now time.Time) (*domain.HistoryEntry, error) {
+	entry := &domain.HistoryEntry{
+		OrderID:   after.OrderID,
+		Version:   version,
+		Action:    action,
+		Actor:     ActorFrom(ctx),
+		NewStatus: after.Status,
+		Reason:    reason,
+		Timestamp: now,
+	}
+	if before == nil {
+		return entry, nil
+	}
+
+	entry.OldStatus = before.Status
+	diff, err := diffOrders(before, after)
+	if err != nil {
+		return nil, err
+	}
+	entry.Diff = diff
+	return entry, nil
+}
+
+// diffOrders - JSON 최상위 필드 단위로 달라진 값 비교
+func diffOrders(before, after *domain.Order) ([]domain.FieldChange, error) {
+	old, err := orderFields(before)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := orderFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]struct{}, len(cur))
+	for f := range old {
+		fields[f] = struct{}{}
+	}
+	for f := range cur {
+		fields[f] = struct{}{}
+	}
+
+	var diff []domain.FieldChange
+	for f := range fields {
+		if historyIgnoredFields[f] || bytes.Equal(old[f], cur[f]) {
+			continue
+		}
+		diff = append(diff, domain.FieldChange{Field: f, Old: old[f], New: cur[f]})
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Field < diff[j].Field })
+	return diff, nil
+}
+
+func orderFields(order *domain.Order) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// cloneOrder - 변경 전 상태를 비교용으로 보관 (항목 슬라이스까지 복사)
+func cloneOrder(order *domain.Order) *domain.Order {
+	c := *order
+	c.Items = append([]domain.OrderItem(nil), order.Items...)
+	c.LineHistory = append([]domain.LineChange(nil), order.LineHistory...)
+	return &c
+}
+
+// GetOrderHistory - 주문의 변경 이력 (오래된 순)
+func (s *OrderService) GetOrderHistory(ctx context.Context, id int) ([]*domain.HistoryEntry, error) {
+	// 주문이 없으면 빈 이력이 아닌 ErrOrderNotFound 반환
+	if _, err := s.orderRepo.GetOrder(ctx, id); err != nil {
+		return nil, err
+	}
+	history, err := s.orderRepo.ListOrderHistory(ctx, id)
+	if err != nil {
+		s.logger.Warn("GetOrderHistory failed", zap.Int("order_id", id), zap.Error(err))
+		return nil, err
+	}
+	return history, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// ErrIdempotencyKeyReused - 같은 멱등성 키로 다른 내용의 주문을 요청한 경우
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different payload")
+
+// requestHash - 멱등성 비교에 사용하는 요청 본문 해시
+func requestHash(req domain.CreateOrderRequest) (string, error) {
+	body, err := json.Marshal(struct {
+		UserID string             `json:"user_id"`
+		Items  []domain.OrderItem `json:"items"`
+	}{req.UserID, req.Items})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash request: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (s *OrderService) replay(rec *repository.IdempotencyRecord, hash string) (*CreateOrderResult, error) {
+	if rec.RequestHash != hash {
+		s.logger.Warn("Idempotency key reused with different payload",
+			zap.String("user_id", rec.UserID),
+			zap.String("idempotency_key", rec.IdempotencyKey),
+			zap.Int("order_id", rec.OrderID))
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	s.logger.Info("Replaying idempotent order response",
+		zap.String("user_id", rec.UserID),
+		zap.String("idempotency_key", rec.IdempotencyKey),
+		zap.Int("order_id", rec.OrderID))
+	return &CreateOrderResult{Response: rec.Response, Replayed: true}, nil
+}
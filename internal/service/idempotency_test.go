@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+)
+
+func TestCreateOrderReplaysSameKeyAndBody(t *testing.T) {
+	svc, store := newTestService(t)
+	ctx := context.Background()
+
+	first, err := svc.CreateOrder(ctx, createRequest("key-1", 2), "req-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Replayed {
+		t.Error("first request marked as replayed")
+	}
+
+	again, err := svc.CreateOrder(ctx, createRequest("key-1", 2), "req-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Replayed || again.Response != first.Response {
+		t.Errorf("replay = %+v, want replayed %+v", again, first.Response)
+	}
+
+	page, err := store.GetOrdersByUser(ctx, repository.UserOrdersQuery{UserID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Orders) != 1 {
+		t.Errorf("stored %d orders, want 1", len(page.Orders))
+	}
+}
+
+func TestCreateOrderRejectsReusedKeyWithDifferentBody(t *testing.T) {
+	svc, store := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.CreateOrder(ctx, createRequest("key-1", 2), "req-1"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := svc.CreateOrder(ctx, createRequest("key-1", 3), "req-2")
+	if !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("err = %v, want ErrIdempotencyKeyReused", err)
+	}
+
+	// 다른 사용자는 같은 키를 따로 쓸 수 있음
+	other := createRequest("key-1", 3)
+	other.UserID = "user-2"
+	if result, err := svc.CreateOrder(ctx, other, "req-3"); err != nil || result.Replayed {
+		t.Errorf("other user's order = %+v, %v, want a new order", result, err)
+	}
+
+	page, err := store.GetOrdersByUser(ctx, repository.UserOrdersQuery{UserID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Orders) != 1 {
+		t.Errorf("stored %d orders for user-1, want 1", len(page.Orders))
+	}
+}
+
+func TestCreateOrderIgnoresExpiredRecord(t *testing.T) {
+	svc, store := newTestService(t)
+	ctx := context.Background()
+
+	// 보관 기간이 지났지만 아직 삭제되지 않은 레코드 (DynamoDB TTL 삭제 지연)
+	past := time.Now().Add(-2 * time.Hour)
+	old := &domain.Order{
+		OrderID:   1,
+		UserID:    "user-1",
+		Items:     []domain.OrderItem{{LineID: 1, ProductID: "PROD-A", Quantity: 1, Price: testPrice, Status: domain.LineStatusActive}},
+		Status:    domain.OrderStatusPending,
+		CreatedAt: past,
+		UpdatedAt: past,
+		Version:   1,
+	}
+	expired := &repository.IdempotencyRecord{
+		UserID:         "user-1",
+		IdempotencyKey: "key-1",
+		RequestHash:    "previous-request",
+		OrderID:        old.OrderID,
+		Response:       domain.CreateOrderResponse{OrderID: old.OrderID, Status: old.Status},
+		CreatedAt:      past,
+		ExpiresAt:      past.Add(time.Hour).Unix(),
+	}
+	if err := store.CreateOrder(ctx, old, expired, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.CreateOrder(ctx, createRequest("key-1", 2), "req-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Replayed || result.Response.OrderID == old.OrderID {
+		t.Errorf("result = %+v, want a new order", result)
+	}
+
+	// 새 레코드로 바뀌어 이후 같은 요청은 새 주문을 재생
+	again, err := svc.CreateOrder(ctx, createRequest("key-1", 2), "req-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Replayed || again.Response.OrderID != result.Response.OrderID {
+		t.Errorf("replay = %+v, want order %d", again, result.Response.OrderID)
+	}
+}
+
+// racingStore - 멱등성 레코드 조회가 한 번 비어 있는 저장소 (같은 키의 동시 요청이 먼저 저장한 경우)
+type racingStore struct {
+	*repository.MemoryOrderRepository
+	missed bool
+}
+
+func (s *racingStore) GetIdempotencyRecord(ctx context.Context, userID, key string) (*repository.IdempotencyRecord, error) {
+	if !s.missed {
+		s.missed = true
+		return nil, repository.ErrIdempotencyRecordNotFound
+	}
+	return s.MemoryOrderRepository.GetIdempotencyRecord(ctx, userID, key)
+}
+
+func TestCreateOrderReplaysConcurrentDuplicate(t *testing.T) {
+	svc, store := newTestService(t)
+	ctx := context.Background()
+	first, err := svc.CreateOrder(ctx, createRequest("key-1", 2), "req-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc.orderRepo = &racingStore{MemoryOrderRepository: store}
+	again, err := svc.CreateOrder(ctx, createRequest("key-1", 2), "req-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Replayed || again.Response.OrderID != first.Response.OrderID {
+		t.Errorf("result = %+v, want replay of order %d", again, first.Response.OrderID)
+	}
+
+	svc.orderRepo = &racingStore{MemoryOrderRepository: store}
+	if _, err := svc.CreateOrder(ctx, createRequest("key-1", 5), "req-3"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("different body err = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
@@ -2,39 +2,61 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/cloud-wave-best-zizon/order-service/internal/catalog"
 	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
 	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/cloud-wave-best-zizon/order-service/internal/idgen"
 	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+type Config struct {
+	// 멱등성 레코드 보관 기간
+	IdempotencyTTL time.Duration
+	// 주문 생성 후 모든 재고 차감 결과가 도착해야 하는 시간 (사가 타임아웃)
+	SagaStepTimeout time.Duration
+}
+
 type OrderService struct {
-	orderRepo *repository.OrderRepository
-	producer  *events.KafkaProducer
+	orderRepo OrderStore
+	relay     Notifier
+	idGen     idgen.IDGenerator
+	catalog   catalog.PriceCatalog
+	cfg       Config
 	logger    *zap.Logger
 }
 
-func NewOrderService(orderRepo *repository.OrderRepository, producer *events.KafkaProducer, logger *zap.Logger) *OrderService {
+func NewOrderService(orderRepo OrderStore, relay Notifier, idGen idgen.IDGenerator, priceCatalog catalog.PriceCatalog, cfg Config, logger *zap.Logger) *OrderService {
+	if cfg.SagaStepTimeout <= 0 {
+		cfg.SagaStepTimeout = 5 * time.Minute
+	}
 	return &OrderService{
 		orderRepo: orderRepo,
-		producer:  producer,
+		relay:     relay,
+		idGen:     idGen,
+		catalog:   priceCatalog,
+		cfg:       cfg,
 		logger:    logger,
 	}
 }
 
-func (s *OrderService) CreateOrder(ctx context.Context, req domain.CreateOrderRequest, requestID string) (*domain.Order, error) {
-	// Context에서 추가 정보 추출
-	userAgent := ""
-	sourceIP := ""
-	if val := ctx.Value("user_agent"); val != nil {
-		userAgent, _ = val.(string)
-	}
-	if val := ctx.Value("source_ip"); val != nil {
-		sourceIP, _ = val.(string)
-	}
+// CreateOrderResult - 주문 생성 결과 (같은 멱등성 키의 재요청이면 Replayed=true)
+type CreateOrderResult struct {
+	Response domain.CreateOrderResponse
+	Replayed bool
+}
+
+func (s *OrderService) CreateOrder(ctx context.Context, req domain.CreateOrderRequest, requestID string) (*CreateOrderResult, error) {
+	// Context에서 요청 주체 정보 추출
+	actor := ActorFrom(ctx)
+	userAgent := actor.UserAgent
+	sourceIP := actor.SourceIP
 
 	// 로깅 강화
 	s.logger.Info("Creating order",
@@ -45,41 +67,52 @@ func (s *OrderService) CreateOrder(ctx context.Context, req domain.CreateOrderRe
 		zap.String("source_ip", sourceIP),
 		zap.Int("items_count", len(req.Items)))
 
-	// Order 생성 - Unix milliseconds를 사용하여 유니크한 OrderID 생성
+	// 이미 처리된 멱등성 키인지 확인
+	hash, err := requestHash(req)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.orderRepo.GetIdempotencyRecord(ctx, req.UserID, req.IdempotencyKey)
+	if err == nil {
+		return s.replay(rec, hash)
+	}
+	if !errors.Is(err, repository.ErrIdempotencyRecordNotFound) {
+		return nil, err
+	}
+
+	// 가격은 클라이언트 값이 아닌 카탈로그 기준으로 계산
+	items, catalogVersion, err := s.priceItems(ctx, req.Items)
+	if err != nil {
+		s.logger.Warn("Order pricing rejected",
+			zap.String("user_id", req.UserID),
+			zap.String("request_id", requestID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	// Order 생성 - 노드별 Snowflake ID로 파드 간에도 충돌 없는 OrderID 생성
+	orderID, err := s.idGen.NextID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate order id: %w", err)
+	}
 	order := &domain.Order{
-		OrderID:        int(time.Now().UnixMilli()),
+		OrderID:        orderID,
 		UserID:         req.UserID,
-		Items:          make([]domain.OrderItem, 0, len(req.Items)),
+		Items:          items,
 		Status:         domain.OrderStatusPending,
+		CatalogVersion: catalogVersion,
 		IdempotencyKey: req.IdempotencyKey,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
+		Version:        1,
 	}
 
 	// Items 처리 및 총액 계산
-	var totalAmount float64
-	for _, item := range req.Items {
-		subtotal := float64(item.Quantity) * item.Price
-		orderItem := domain.OrderItem{
-			ProductID:   item.ProductID,
-			ProductName: item.ProductName,
-			Quantity:    item.Quantity,
-			Price:       item.Price,
-		}
-		order.Items = append(order.Items, orderItem)
-		totalAmount += subtotal
-	}
-	order.TotalAmount = totalAmount
-
-	// DynamoDB에 저장
-	if err := s.orderRepo.CreateOrder(ctx, order); err != nil {
-		s.logger.Error("Failed to save order",
-			zap.Int("order_id", order.OrderID),
-			zap.Error(err))
+	if err := order.Recalculate(); err != nil {
 		return nil, err
 	}
 
-	// Kafka 이벤트 발행
+	// Kafka 이벤트는 주문과 같은 트랜잭션으로 아웃박스에 기록 후 릴레이가 발행
 	event := events.OrderCreatedEvent{
 		EventID:        uuid.New().String(),
 		OrderID:        order.OrderID,
@@ -90,24 +123,103 @@ func (s *OrderService) CreateOrder(ctx context.Context, req domain.CreateOrderRe
 		Timestamp:      time.Now(),
 		RequestID:      requestID,
 		IdempotencyKey: req.IdempotencyKey,
+		CatalogVersion: order.CatalogVersion,
 		UserAgent:      userAgent,  // context에서 가져온 값
 		SourceIP:       sourceIP,   // context에서 가져온 값
 	}
 
-	if err := s.producer.PublishOrderCreated(event); err != nil {
-		// 이벤트 발행 실패 시 로그만 (Eventual Consistency)
-		s.logger.Error("Failed to publish event",
+	msg, err := repository.NewOutboxMessage(events.TopicOrderEvents, events.EventTypeOrderCreated, strconv.Itoa(event.OrderID), event)
+	if err != nil {
+		return nil, err
+	}
+
+	response := domain.CreateOrderResponse{
+		OrderID: order.OrderID,
+		Status:  order.Status,
+		Message: "Order created successfully",
+	}
+	idem := &repository.IdempotencyRecord{
+		UserID:         req.UserID,
+		IdempotencyKey: req.IdempotencyKey,
+		RequestHash:    hash,
+		OrderID:        order.OrderID,
+		Response:       response,
+		CreatedAt:      order.CreatedAt,
+		ExpiresAt:      order.CreatedAt.Add(s.cfg.IdempotencyTTL).Unix(),
+	}
+
+	entry, err := newHistoryEntry(ctx, domain.HistoryActionCreated, nil, order, order.Version, "", order.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// 재고 차감 사가도 주문과 함께 만들어 결과가 하나도 오지 않아도 타임아웃되게 함
+	saga := domain.NewSaga(order, order.CreatedAt.Add(s.cfg.SagaStepTimeout), order.CreatedAt)
+
+	// DynamoDB에 저장
+	if err := s.orderRepo.CreateOrder(ctx, order, idem, entry, &repository.SagaWrite{Saga: saga, Create: true}, msg); err != nil {
+		if errors.Is(err, repository.ErrIdempotencyKeyExists) {
+			// 동시에 들어온 같은 키의 요청이 먼저 저장됨
+			rec, getErr := s.orderRepo.GetIdempotencyRecord(ctx, req.UserID, req.IdempotencyKey)
+			if getErr != nil {
+				return nil, getErr
+			}
+			return s.replay(rec, hash)
+		}
+		s.logger.Error("Failed to save order",
 			zap.Int("order_id", order.OrderID),
 			zap.Error(err))
-		// TODO: Outbox Pattern 구현
+		return nil, err
 	}
+	s.relay.Notify()
 
 	s.logger.Info("Order created successfully",
 		zap.Int("order_id", order.OrderID),
 		zap.String("user_id", order.UserID),
-		zap.Float64("total_amount", order.TotalAmount))
+		zap.Stringer("total_amount", order.TotalAmount))
 
-	return order, nil
+	return &CreateOrderResult{Response: response}, nil
+}
+
+// ErrNotPending - 재고 처리 결과를 기다리는(PENDING) 주문이 아님
+var ErrNotPending = errors.New("order is not pending")
+
+// RepublishOrderCreated - 재고 처리 결과가 오지 않은 PENDING 주문의 OrderCreated 이벤트를 다시 발행
+// 새 event_id로 발행하므로 소비자는 order_id 기준으로 중복을 걸러야 함
+func (s *OrderService) RepublishOrderCreated(ctx context.Context, id int) error {
+	order, err := s.orderRepo.GetOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+	if order.Status != domain.OrderStatusPending {
+		return fmt.Errorf("%w: order %d is %s", ErrNotPending, id, order.Status)
+	}
+
+	event := events.OrderCreatedEvent{
+		EventID:        uuid.New().String(),
+		OrderID:        order.OrderID,
+		UserID:         order.UserID,
+		TotalAmount:    order.TotalAmount,
+		Items:          order.Items,
+		Status:         string(order.Status),
+		Timestamp:      time.Now(),
+		RequestID:      ActorFrom(ctx).RequestID,
+		IdempotencyKey: order.IdempotencyKey,
+		CatalogVersion: order.CatalogVersion,
+	}
+	msg, err := repository.NewOutboxMessage(events.TopicOrderEvents, events.EventTypeOrderCreated, strconv.Itoa(event.OrderID), event)
+	if err != nil {
+		return err
+	}
+	if err := s.orderRepo.EnqueueOutbox(ctx, msg); err != nil {
+		return err
+	}
+	s.relay.Notify()
+
+	s.logger.Info("OrderCreated event republished",
+		zap.Int("order_id", order.OrderID),
+		zap.String("event_id", event.EventID))
+	return nil
 }
 
 func (s *OrderService) GetOrder(ctx context.Context, id int) (*domain.Order, error) {
@@ -117,4 +229,24 @@ func (s *OrderService) GetOrder(ctx context.Context, id int) (*domain.Order, err
 		return nil, err
 	}
 	return order, nil
-}
\ No newline at end of file
+}
+
+// ListUserOrders - 사용자 주문 목록 한 페이지 조회
+func (s *OrderService) ListUserOrders(ctx context.Context, q repository.UserOrdersQuery) (*repository.OrderPage, error) {
+	page, err := s.orderRepo.GetOrdersByUser(ctx, q)
+	if err != nil {
+		s.logger.Warn("ListUserOrders failed", zap.String("user_id", q.UserID), zap.Error(err))
+		return nil, err
+	}
+	return page, nil
+}
+
+// ListOrdersByStatus - 운영용 상태/생성일 주문 목록 한 페이지 조회
+func (s *OrderService) ListOrdersByStatus(ctx context.Context, q repository.StatusOrdersQuery) (*repository.OrderPage, error) {
+	page, err := s.orderRepo.ListOrdersByStatus(ctx, q)
+	if err != nil {
+		s.logger.Warn("ListOrdersByStatus failed", zap.String("status", string(q.Status)), zap.Error(err))
+		return nil, err
+	}
+	return page, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/catalog"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/idgen"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+var testPrice = domain.Money{Amount: 1000, Currency: "KRW"}
+
+// fixedCatalog - 모든 상품이 testPrice인 가격표
+type fixedCatalog struct{}
+
+func (fixedCatalog) GetPrices(ctx context.Context, productIDs []string) (*catalog.Quote, error) {
+	quote := &catalog.Quote{Version: "v1", Prices: make(map[string]catalog.Price)}
+	for _, id := range productIDs {
+		quote.Prices[id] = catalog.Price{ProductID: id, Name: id, Price: testPrice, Version: "v1"}
+	}
+	return quote, nil
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify() {}
+
+func newTestService(t *testing.T) (*OrderService, *repository.MemoryOrderRepository) {
+	t.Helper()
+	ids, err := idgen.NewSnowflake(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := repository.NewMemoryOrderRepository()
+	svc := NewOrderService(store, nopNotifier{}, ids, fixedCatalog{}, Config{
+		IdempotencyTTL:  time.Hour,
+		SagaStepTimeout: time.Minute,
+	}, zap.NewNop())
+	return svc, store
+}
+
+func createRequest(key string, quantity int) domain.CreateOrderRequest {
+	return domain.CreateOrderRequest{
+		UserID:         "user-1",
+		Items:          []domain.OrderItem{{ProductID: "PROD-A", Quantity: quantity, Price: testPrice}},
+		IdempotencyKey: key,
+	}
+}
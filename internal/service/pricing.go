@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/catalog"
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+// ErrPriceMismatch - 클라이언트가 제시한 가격이 카탈로그 가격과 다름
+var ErrPriceMismatch = errors.New("quoted price does not match catalog price")
+
+// PriceMismatchError - 어떤 상품의 가격이 달랐는지 클라이언트에 알려주기 위한 상세 정보
+type PriceMismatchError struct {
+	ProductID    string
+	QuotedPrice  domain.Money
+	CatalogPrice domain.Money
+}
+
+func (e *PriceMismatchError) Error() string {
+	return fmt.Sprintf("%s: product %s quoted %s, catalog %s",
+		ErrPriceMismatch, e.ProductID, e.QuotedPrice, e.CatalogPrice)
+}
+
+func (e *PriceMismatchError) Unwrap() error {
+	return ErrPriceMismatch
+}
+
+// priceItems - 카탈로그 단가로 주문 항목을 만들고, 제시 가격이 다르면 거부
+func (s *OrderService) priceItems(ctx context.Context, items []domain.OrderItem) ([]domain.OrderItem, string, error) {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+
+	quote, err := s.catalog.GetPrices(ctx, ids)
+	if err != nil {
+		return nil, "", err
+	}
+
+	priced := make([]domain.OrderItem, 0, len(items))
+	for _, item := range items {
+		p, ok := quote.Prices[item.ProductID]
+		if !ok {
+			return nil, "", fmt.Errorf("%w: %s", catalog.ErrProductNotFound, item.ProductID)
+		}
+		if item.Price != p.Price {
+			return nil, "", &PriceMismatchError{
+				ProductID:    item.ProductID,
+				QuotedPrice:  item.Price,
+				CatalogPrice: p.Price,
+			}
+		}
+
+		name := item.ProductName
+		if p.Name != "" {
+			name = p.Name
+		}
+		priced = append(priced, domain.OrderItem{
+			LineID:      len(priced) + 1,
+			ProductID:   item.ProductID,
+			ProductName: name,
+			Quantity:    item.Quantity,
+			Price:       p.Price,
+			Status:      domain.LineStatusActive,
+		})
+	}
+	return priced, quote.Version, nil
+}
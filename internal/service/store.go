@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+)
+
+// OrderStore - 주문 영속성 계층 (DynamoDB / 인메모리 구현)
+type OrderStore interface {
+	CreateOrder(ctx context.Context, order *domain.Order, idem *repository.IdempotencyRecord, history *domain.HistoryEntry, saga *repository.SagaWrite, outbox ...*repository.OutboxMessage) error
+	GetOrder(ctx context.Context, id int) (*domain.Order, error)
+	GetOrdersByUser(ctx context.Context, q repository.UserOrdersQuery) (*repository.OrderPage, error)
+	ListOrdersByStatus(ctx context.Context, q repository.StatusOrdersQuery) (*repository.OrderPage, error)
+	UpdateOrderStatus(ctx context.Context, id int, createdAt time.Time, from, to domain.OrderStatus, updatedAt time.Time, history *domain.HistoryEntry, saga *repository.SagaWrite, outbox ...*repository.OutboxMessage) error
+	UpdateOrder(ctx context.Context, order *domain.Order, expectedVersion int, history *domain.HistoryEntry, saga *repository.SagaWrite, outbox ...*repository.OutboxMessage) error
+	ListOrderHistory(ctx context.Context, orderID int) ([]*domain.HistoryEntry, error)
+	GetIdempotencyRecord(ctx context.Context, userID, key string) (*repository.IdempotencyRecord, error)
+	EnqueueOutbox(ctx context.Context, msgs ...*repository.OutboxMessage) error
+}
+
+// Notifier - 아웃박스에 새 메시지가 기록되었음을 릴레이에 알림
+type Notifier interface {
+	Notify()
+}
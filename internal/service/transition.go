@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/events"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/order-service/internal/statemachine"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Transition - 상태 머신 규칙에 따라 주문 상태를 변경하고 OrderStatusChangedEvent 발행
+// plan이 있으면 그 사가와 아웃박스 메시지(보상 이벤트 등)를 상태 변경과 같은 트랜잭션으로 기록
+func (s *OrderService) Transition(ctx context.Context, id int, to domain.OrderStatus, reason string, plan repository.SagaPlan) (*domain.Order, error) {
+	order, err := s.orderRepo.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := order.Status
+	if err := statemachine.Validate(from, to); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	event := events.OrderStatusChangedEvent{
+		EventID:    uuid.New().String(),
+		OrderID:    order.OrderID,
+		UserID:     order.UserID,
+		FromStatus: string(from),
+		ToStatus:   string(to),
+		Reason:     reason,
+		Timestamp:  now,
+	}
+	msg, err := repository.NewOutboxMessage(events.TopicOrderEvents, events.EventTypeOrderStatusChanged, strconv.Itoa(event.OrderID), event)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *order
+	updated.Status = to
+	entry, err := newHistoryEntry(ctx, domain.HistoryActionStatusChanged, order, &updated, order.Version+1, reason, now)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox := []*repository.OutboxMessage{msg}
+	var saga *repository.SagaWrite
+	if plan != nil {
+		var extra []*repository.OutboxMessage
+		if saga, extra, err = plan(order, &updated); err != nil {
+			return nil, err
+		}
+		outbox = append(outbox, extra...)
+	}
+
+	if err := s.orderRepo.UpdateOrderStatus(ctx, id, order.CreatedAt, from, to, now, entry, saga, outbox...); err != nil {
+		s.logger.Warn("Order transition failed",
+			zap.Int("order_id", id),
+			zap.String("from", string(from)),
+			zap.String("to", string(to)),
+			zap.Error(err))
+		return nil, err
+	}
+	s.relay.Notify()
+
+	order.Status = to
+	order.UpdatedAt = now
+	order.Version++
+
+	s.logger.Info("Order status changed",
+		zap.Int("order_id", id),
+		zap.String("from", string(from)),
+		zap.String("to", string(to)),
+		zap.String("reason", reason))
+
+	return order, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/statemachine"
+)
+
+func TestTransitionRejectsInvalidTransition(t *testing.T) {
+	svc, store := newTestService(t)
+	ctx := context.Background()
+	created, err := svc.CreateOrder(ctx, createRequest("key-1", 1), "req-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := created.Response.OrderID
+
+	_, err = svc.Transition(ctx, id, domain.OrderStatusShipped, "skip confirmation", nil)
+	if !errors.Is(err, statemachine.ErrInvalidTransition) {
+		t.Fatalf("PENDING -> SHIPPED err = %v, want ErrInvalidTransition", err)
+	}
+	order, err := store.GetOrder(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != domain.OrderStatusPending || order.Version != 1 {
+		t.Errorf("order after rejected transition = %s v%d, want PENDING v1", order.Status, order.Version)
+	}
+
+	// 허용된 전이 뒤에는 종료 상태에서 더 이동할 수 없음
+	if _, err := svc.Transition(ctx, id, domain.OrderStatusCancelled, "customer request", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Transition(ctx, id, domain.OrderStatusConfirmed, "late stock result", nil); !errors.Is(err, statemachine.ErrInvalidTransition) {
+		t.Errorf("CANCELLED -> CONFIRMED err = %v, want ErrInvalidTransition", err)
+	}
+
+	// 부분 취소도 취소할 수 없는 상태면 같은 에러
+	if _, err := svc.CancelLines(ctx, id, []domain.CancelLine{{LineID: 1, Quantity: 1}}, "customer request", nil); !errors.Is(err, statemachine.ErrInvalidTransition) {
+		t.Errorf("cancel lines of CANCELLED order err = %v, want ErrInvalidTransition", err)
+	}
+}
@@ -0,0 +1,49 @@
+package statemachine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+var ErrInvalidTransition = errors.New("invalid order status transition")
+
+// transitions - 상태별 허용되는 다음 상태
+//
+//	PENDING ─→ CONFIRMED ─→ SHIPPED ─→ DELIVERED
+//	   │           │
+//	   └───────────┴─→ CANCELLED
+var transitions = map[domain.OrderStatus][]domain.OrderStatus{
+	domain.OrderStatusPending:   {domain.OrderStatusConfirmed, domain.OrderStatusCancelled},
+	domain.OrderStatusConfirmed: {domain.OrderStatusShipped, domain.OrderStatusCancelled},
+	domain.OrderStatusShipped:   {domain.OrderStatusDelivered},
+}
+
+// CanTransition - from에서 to로 이동 가능한지 여부
+func CanTransition(from, to domain.OrderStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate - 허용되지 않는 전이면 ErrInvalidTransition을 감싼 에러 반환
+func Validate(from, to domain.OrderStatus) error {
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// Next - from에서 이동 가능한 상태 목록
+func Next(from domain.OrderStatus) []domain.OrderStatus {
+	return append([]domain.OrderStatus(nil), transitions[from]...)
+}
+
+// IsTerminal - 더 이상 전이할 수 없는 상태인지 여부
+func IsTerminal(status domain.OrderStatus) bool {
+	return len(transitions[status]) == 0
+}
@@ -0,0 +1,92 @@
+package statemachine
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+)
+
+var allStatuses = []domain.OrderStatus{
+	domain.OrderStatusPending,
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusShipped,
+	domain.OrderStatusDelivered,
+	domain.OrderStatusCancelled,
+}
+
+// allowed - 문서의 상태 다이어그램과 같은 전이 표
+var allowed = map[[2]domain.OrderStatus]bool{
+	{domain.OrderStatusPending, domain.OrderStatusConfirmed}:   true,
+	{domain.OrderStatusPending, domain.OrderStatusCancelled}:   true,
+	{domain.OrderStatusConfirmed, domain.OrderStatusShipped}:   true,
+	{domain.OrderStatusConfirmed, domain.OrderStatusCancelled}: true,
+	{domain.OrderStatusShipped, domain.OrderStatusDelivered}:   true,
+}
+
+func TestTransitionTable(t *testing.T) {
+	// 모든 (from, to) 조합 - 허용 목록 외에는 모두 거부
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			t.Run(fmt.Sprintf("%s->%s", from, to), func(t *testing.T) {
+				want := allowed[[2]domain.OrderStatus{from, to}]
+				if got := CanTransition(from, to); got != want {
+					t.Errorf("CanTransition = %v, want %v", got, want)
+				}
+				err := Validate(from, to)
+				if want {
+					if err != nil {
+						t.Errorf("Validate = %v, want nil", err)
+					}
+					return
+				}
+				if !errors.Is(err, ErrInvalidTransition) {
+					t.Fatalf("Validate = %v, want ErrInvalidTransition", err)
+				}
+				if err == ErrInvalidTransition || !strings.Contains(err.Error(), fmt.Sprintf("%s -> %s", from, to)) {
+					t.Errorf("Validate = %q, want wrapped error naming the transition", err)
+				}
+			})
+		}
+	}
+}
+
+func TestTransitionUnknownStatus(t *testing.T) {
+	for _, tt := range []struct{ from, to domain.OrderStatus }{
+		{"", domain.OrderStatusConfirmed},
+		{"REFUNDED", domain.OrderStatusCancelled},
+		{domain.OrderStatusPending, "REFUNDED"},
+		{domain.OrderStatusPending, ""},
+	} {
+		if err := Validate(tt.from, tt.to); !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("Validate(%q, %q) = %v, want ErrInvalidTransition", tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestNextAndIsTerminal(t *testing.T) {
+	for _, from := range allStatuses {
+		var want []domain.OrderStatus
+		for _, to := range allStatuses {
+			if allowed[[2]domain.OrderStatus{from, to}] {
+				want = append(want, to)
+			}
+		}
+		if got := Next(from); !reflect.DeepEqual(got, want) {
+			t.Errorf("Next(%s) = %v, want %v", from, got, want)
+		}
+		if got := IsTerminal(from); got != (len(want) == 0) {
+			t.Errorf("IsTerminal(%s) = %v, want %v", from, got, len(want) == 0)
+		}
+	}
+
+	// 반환된 목록을 바꿔도 전이 표는 그대로
+	next := Next(domain.OrderStatusPending)
+	next[0] = domain.OrderStatusDelivered
+	if CanTransition(domain.OrderStatusPending, domain.OrderStatusDelivered) {
+		t.Error("modifying Next result changed the transition table")
+	}
+}
@@ -0,0 +1,312 @@
+package sweeper
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/order-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/order-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/order-service/internal/service"
+	"github.com/cloud-wave-best-zizon/order-service/internal/statemachine"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 리더 선출에 쓰는 리스 이름 (모든 인스턴스가 같은 이름으로 경쟁)
+const leaseName = "order-sweeper"
+
+// Policy - 오래된 PENDING 주문 처리 방식
+type Policy string
+
+const (
+	// PolicyRepublish - OrderCreated 이벤트를 다시 발행해 재고 처리를 재요청
+	PolicyRepublish Policy = "republish"
+	// PolicyCancel - 주문을 취소하고 이미 차감된 재고는 CompensationEvent로 복구
+	PolicyCancel Policy = "cancel"
+)
+
+// /debug/vars의 order_sweeper 항목
+var (
+	metrics      = expvar.NewMap("order_sweeper")
+	leaderMetric = new(expvar.Int)
+)
+
+func init() {
+	metrics.Set("leader", leaderMetric)
+}
+
+// Store - 스위퍼가 사용하는 주문/사가 조회와 리더 선출 리스
+type Store interface {
+	ListOrdersByStatus(ctx context.Context, q repository.StatusOrdersQuery) (*repository.OrderPage, error)
+	GetSaga(ctx context.Context, orderID int) (*domain.Saga, error)
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// Republisher - OrderCreated 이벤트 재발행
+type Republisher interface {
+	RepublishOrderCreated(ctx context.Context, id int) error
+}
+
+// Canceller - 주문 취소와 보상 이벤트 발행
+type Canceller interface {
+	CancelOrder(ctx context.Context, orderID int, reason string) (*domain.Order, error)
+}
+
+type Config struct {
+	Interval time.Duration
+	// 생성 후 이 시간이 지나도록 재고 처리 결과가 없는 PENDING 주문을 처리
+	Deadline  time.Duration
+	Policy    Policy
+	BatchSize int32
+	// 리더가 응답 없이 사라졌을 때 다른 인스턴스가 이어받기까지의 시간 (Interval보다 길어야 함)
+	LeaseTTL time.Duration
+	// republish 정책에서 주문당 최대 재발행 횟수 (Deadline 간격으로 재발행)
+	MaxRepublish int
+	// 리스 소유자 ID (비어 있으면 호스트 이름으로 생성)
+	Holder string
+}
+
+// republishState - 리더가 된 이후 주문별 재발행 기록 (리더가 바뀌면 처음부터 다시 셈)
+type republishState struct {
+	count int
+	last  time.Time
+}
+
+// Sweeper - 재고 처리 결과가 오지 않아 PENDING에 머문 주문을 주기적으로 재발행 또는 취소
+// 여러 인스턴스 중 리스를 가진 하나만 실행
+type Sweeper struct {
+	store       Store
+	republisher Republisher
+	canceller   Canceller
+	cfg         Config
+	logger      *zap.Logger
+
+	leader      bool
+	republished map[int]*republishState
+}
+
+func NewSweeper(store Store, republisher Republisher, canceller Canceller, cfg Config, logger *zap.Logger) *Sweeper {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Deadline <= 0 {
+		cfg.Deadline = 15 * time.Minute
+	}
+	if cfg.Policy == "" {
+		cfg.Policy = PolicyRepublish
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.LeaseTTL <= cfg.Interval {
+		cfg.LeaseTTL = 2 * cfg.Interval
+	}
+	if cfg.MaxRepublish <= 0 {
+		cfg.MaxRepublish = 3
+	}
+	if cfg.Holder == "" {
+		host, _ := os.Hostname()
+		cfg.Holder = fmt.Sprintf("%s-%s", host, uuid.New().String()[:8])
+	}
+
+	return &Sweeper{
+		store:       store,
+		republisher: republisher,
+		canceller:   canceller,
+		cfg:         cfg,
+		logger:      logger,
+		republished: make(map[int]*republishState),
+	}
+}
+
+// Run - Interval마다 리스를 획득/갱신하고, 리더일 때만 스윕 (종료 시 리스 반납)
+func (s *Sweeper) Run(ctx context.Context) {
+	s.logger.Info("Order sweeper started",
+		zap.String("policy", string(s.cfg.Policy)),
+		zap.Duration("deadline", s.cfg.Deadline),
+		zap.Duration("interval", s.cfg.Interval),
+		zap.String("holder", s.cfg.Holder))
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if s.elect(ctx) {
+			s.sweep(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.release()
+			s.logger.Info("Order sweeper stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// elect - 리스를 획득/갱신하고 리더 여부를 반환
+func (s *Sweeper) elect(ctx context.Context) bool {
+	ok, err := s.store.AcquireLease(ctx, leaseName, s.cfg.Holder, s.cfg.LeaseTTL)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("Failed to acquire sweeper lease", zap.Error(err))
+		}
+		ok = false
+	}
+
+	if ok != s.leader {
+		if ok {
+			s.logger.Info("Became order sweeper leader", zap.String("holder", s.cfg.Holder))
+			leaderMetric.Set(1)
+		} else {
+			s.logger.Info("Lost order sweeper leadership", zap.String("holder", s.cfg.Holder))
+			leaderMetric.Set(0)
+			s.republished = make(map[int]*republishState)
+		}
+		s.leader = ok
+	}
+	return ok
+}
+
+func (s *Sweeper) release() {
+	if !s.leader {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.store.ReleaseLease(ctx, leaseName, s.cfg.Holder); err != nil {
+		s.logger.Warn("Failed to release sweeper lease", zap.Error(err))
+	}
+	s.leader = false
+	leaderMetric.Set(0)
+}
+
+// sweep - Deadline보다 오래된 PENDING 주문을 오래된 순으로 모두 처리
+func (s *Sweeper) sweep(ctx context.Context) {
+	metrics.Add("sweeps", 1)
+	now := time.Now()
+	cutoff := now.Add(-s.cfg.Deadline)
+	q := repository.StatusOrdersQuery{
+		Status:        domain.OrderStatusPending,
+		CreatedBefore: cutoff,
+		Limit:         s.cfg.BatchSize,
+	}
+
+	seen := make(map[int]bool)
+	for {
+		page, err := s.store.ListOrdersByStatus(ctx, q)
+		if err != nil {
+			if ctx.Err() == nil {
+				metrics.Add("failures", 1)
+				s.logger.Error("Failed to list stale orders", zap.Error(err))
+			}
+			return
+		}
+
+		for _, order := range page.Orders {
+			if ctx.Err() != nil {
+				return
+			}
+			// 인덱스는 초 단위로 비교하므로 경계의 주문은 다시 확인
+			if order.CreatedAt.After(cutoff) {
+				continue
+			}
+			seen[order.OrderID] = true
+			s.handle(ctx, order, now)
+		}
+
+		if page.NextKey == nil {
+			break
+		}
+		// 긴 스윕 도중 리스가 만료되어 다른 인스턴스가 이어받았으면 중단
+		if !s.elect(ctx) {
+			return
+		}
+		q.StartKey = page.NextKey
+	}
+
+	// 더 이상 PENDING이 아닌 주문의 재발행 기록 정리
+	for id := range s.republished {
+		if !seen[id] {
+			delete(s.republished, id)
+		}
+	}
+}
+
+func (s *Sweeper) handle(ctx context.Context, order *domain.Order, now time.Time) {
+	// 재고 결과를 하나라도 받은 주문은 사가 오케스트레이터가 타임아웃/보상을 처리
+	saga, err := s.store.GetSaga(ctx, order.OrderID)
+	if err == nil && !saga.AwaitingResults() {
+		metrics.Add("orders_skipped", 1)
+		return
+	}
+	if err != nil && !errors.Is(err, repository.ErrSagaNotFound) {
+		metrics.Add("failures", 1)
+		s.logger.Error("Failed to load saga of stale order", zap.Int("order_id", order.OrderID), zap.Error(err))
+		return
+	}
+
+	switch s.cfg.Policy {
+	case PolicyCancel:
+		s.cancel(ctx, order, now)
+	default:
+		s.republish(ctx, order, now)
+	}
+}
+
+func (s *Sweeper) republish(ctx context.Context, order *domain.Order, now time.Time) {
+	state := s.republished[order.OrderID]
+	if state == nil {
+		state = &republishState{}
+		s.republished[order.OrderID] = state
+	}
+	if state.count >= s.cfg.MaxRepublish || (!state.last.IsZero() && now.Sub(state.last) < s.cfg.Deadline) {
+		metrics.Add("orders_skipped", 1)
+		return
+	}
+
+	if err := s.republisher.RepublishOrderCreated(ctx, order.OrderID); err != nil {
+		if errors.Is(err, service.ErrNotPending) || errors.Is(err, repository.ErrOrderNotFound) {
+			metrics.Add("orders_skipped", 1)
+			return
+		}
+		metrics.Add("failures", 1)
+		s.logger.Error("Failed to republish stale order", zap.Int("order_id", order.OrderID), zap.Error(err))
+		return
+	}
+
+	state.count++
+	state.last = now
+	metrics.Add("orders_republished", 1)
+	if state.count == s.cfg.MaxRepublish {
+		s.logger.Warn("Stale order reached republish limit, manual action required",
+			zap.Int("order_id", order.OrderID),
+			zap.Int("attempts", state.count),
+			zap.Time("created_at", order.CreatedAt))
+	}
+}
+
+func (s *Sweeper) cancel(ctx context.Context, order *domain.Order, now time.Time) {
+	reason := fmt.Sprintf("stock result not received within %s", s.cfg.Deadline)
+	if _, err := s.canceller.CancelOrder(ctx, order.OrderID, reason); err != nil {
+		// 목록 조회 이후 다른 경로로 상태가 바뀐 주문
+		if errors.Is(err, statemachine.ErrInvalidTransition) || errors.Is(err, repository.ErrStatusConflict) {
+			metrics.Add("orders_skipped", 1)
+			return
+		}
+		metrics.Add("failures", 1)
+		s.logger.Error("Failed to cancel stale order", zap.Int("order_id", order.OrderID), zap.Error(err))
+		return
+	}
+
+	metrics.Add("orders_cancelled", 1)
+	s.logger.Info("Stale order cancelled",
+		zap.Int("order_id", order.OrderID),
+		zap.Duration("age", now.Sub(order.CreatedAt)))
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestLoadNodeID(t *testing.T) {
+	// 운영 명령은 NODE_ID 없이 실행되고, 서버 기동 시에만 요구
+	t.Setenv("NODE_ID", "")
+	os.Unsetenv("NODE_ID")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load without NODE_ID: %v", err)
+	}
+	if err := cfg.RequireNodeID(); err == nil {
+		t.Error("missing NODE_ID accepted for the server")
+	}
+
+	for _, v := range []string{"-1", "1024"} {
+		t.Setenv("NODE_ID", v)
+		if _, err := Load(); err == nil {
+			t.Errorf("NODE_ID=%s accepted", v)
+		}
+	}
+
+	for _, v := range []string{"0", "1023"} {
+		t.Setenv("NODE_ID", v)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strconv.Itoa(cfg.NodeID) != v || cfg.RequireNodeID() != nil {
+			t.Errorf("NODE_ID=%s: NodeID = %d, RequireNodeID = %v", v, cfg.NodeID, cfg.RequireNodeID())
+		}
+	}
+}
+
+func TestLoadAdminPrincipals(t *testing.T) {
+	t.Setenv("NODE_ID", "1")
+
+	t.Setenv("ADMIN_PRINCIPALS", "spiffe://example.org/ns/ops/sa/admin,spiffe://example.org/ns/ops/sa/oncall")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.AdminPrincipals) != 2 || cfg.AdminPrincipals[1] != "spiffe://example.org/ns/ops/sa/oncall" {
+		t.Errorf("AdminPrincipals = %v", cfg.AdminPrincipals)
+	}
+
+	t.Setenv("ADMIN_PRINCIPALS", "admin")
+	if _, err := Load(); err == nil {
+		t.Error("non-SPIFFE ADMIN_PRINCIPALS accepted")
+	}
+}
@@ -0,0 +1,69 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Signer - 페이지네이션 커서를 HMAC으로 서명해 클라이언트가 위조할 수 없게 함
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{key: secret}
+}
+
+// NewRandomSigner - 비밀키가 설정되지 않았을 때 사용 (재시작/다른 파드에서는 커서가 무효)
+func NewRandomSigner() (*Signer, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate cursor key: %w", err)
+	}
+	return NewSigner(key), nil
+}
+
+// Encode - v를 JSON으로 직렬화해 "<payload>.<signature>" 형태의 불투명 문자열로 변환
+func (s *Signer) Encode(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(s.sign(payload)), nil
+}
+
+// Decode - 서명을 검증한 뒤 v로 역직렬화
+func (s *Signer) Decode(token string, v interface{}) error {
+	enc := base64.RawURLEncoding
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+	payload, err := enc.DecodeString(payloadPart)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, s.sign(payload)) {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func (s *Signer) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
 	"go.uber.org/zap"
 )
 
@@ -20,6 +22,40 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
+// Principal - mTLS로 검증된 클라이언트 인증서의 SPIFFE ID를 "principal"에 저장
+// 인증서 검증은 TLS 핸드셰이크에서 끝나므로 평문 포트(ALB) 요청은 principal이 없음
+func Principal() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if state := c.Request.TLS; state != nil && len(state.PeerCertificates) > 0 {
+			if id, err := x509svid.IDFromCert(state.PeerCertificates[0]); err == nil {
+				c.Set("principal", id.String())
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequirePrincipal - principal이 allowed 중 하나인 요청만 통과
+// 평문 포트(ALB) 요청은 principal이 없으므로 401, 다른 서비스의 인증서면 403
+func RequirePrincipal(allowed []string) gin.HandlerFunc {
+	set := make(map[string]bool, len(allowed))
+	for _, id := range allowed {
+		set[id] = true
+	}
+	return func(c *gin.Context) {
+		principal := c.GetString("principal")
+		if principal == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "mTLS client certificate required"})
+			return
+		}
+		if !set[principal] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "principal not allowed"})
+			return
+		}
+		c.Next()
+	}
+}
+
 func Logger(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
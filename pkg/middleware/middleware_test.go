@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func svidCert(t *testing.T, spiffeID string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uri, err := url.Parse(spiffeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{uri},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func principalOf(t *testing.T, state *tls.ConnectionState) string {
+	t.Helper()
+	var got string
+	router := gin.New()
+	router.Use(Principal())
+	router.GET("/", func(c *gin.Context) { got = c.GetString("principal") })
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.TLS = state
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	return got
+}
+
+func TestPrincipal(t *testing.T) {
+	const id = "spiffe://example.org/ns/ops/sa/admin"
+	if got := principalOf(t, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{svidCert(t, id)}}); got != id {
+		t.Errorf("mTLS principal = %q, want %q", got, id)
+	}
+	if got := principalOf(t, nil); got != "" {
+		t.Errorf("plain HTTP principal = %q, want none", got)
+	}
+}
+
+func TestRequirePrincipal(t *testing.T) {
+	const admin = "spiffe://example.org/ns/ops/sa/admin"
+	router := gin.New()
+	router.Use(Principal())
+	router.GET("/", RequirePrincipal([]string{admin}), func(c *gin.Context) { c.Status(204) })
+
+	tests := []struct {
+		name  string
+		state *tls.ConnectionState
+		want  int
+	}{
+		{"plain HTTP", nil, 401},
+		{"other service", &tls.ConnectionState{PeerCertificates: []*x509.Certificate{svidCert(t, "spiffe://example.org/ns/shop/sa/web")}}, 403},
+		{"admin", &tls.ConnectionState{PeerCertificates: []*x509.Certificate{svidCert(t, admin)}}, 204},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.TLS = tt.state
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
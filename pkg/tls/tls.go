@@ -4,6 +4,7 @@ import (
     "context"
     "crypto/tls"
     "fmt"
+    "sync"
     "time"
     
     "github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
@@ -16,7 +17,10 @@ type TLSConfig struct {
     SocketPath   string `envconfig:"SPIRE_SOCKET_PATH" default:"unix:///run/spire/sockets/agent.sock"`
 }
 
-var x509Source *workloadapi.X509Source
+var (
+    x509Source   *workloadapi.X509Source
+    x509SourceMu sync.Mutex
+)
 
 func LoadTLSConfig(cfg *TLSConfig, logger *zap.Logger) (*tls.Config, error) {
     if !cfg.Enabled {
@@ -24,11 +28,34 @@ func LoadTLSConfig(cfg *TLSConfig, logger *zap.Logger) (*tls.Config, error) {
         return nil, nil
     }
     
-    ctx := context.Background()
+    source, err := getX509Source(cfg)
+    if err != nil {
+        return nil, err
+    }
+    
+    // mTLS 서버 설정 생성
+    tlsConfig := tlsconfig.MTLSServerConfig(source, source, tlsconfig.AuthorizeAny())
+    tlsConfig.MinVersion = tls.VersionTLS12
+    
+    logger.Info("SPIRE TLS configuration loaded",
+        zap.String("socket_path", cfg.SocketPath),
+        zap.Bool("mtls_enabled", true))
+    
+    return tlsConfig, nil
+}
+
+// getX509Source - SPIRE Workload API X509 소스 (서버 mTLS와 Kafka 클라이언트 인증서가 공유)
+func getX509Source(cfg *TLSConfig) (*workloadapi.X509Source, error) {
+    x509SourceMu.Lock()
+    defer x509SourceMu.Unlock()
+    
+    if x509Source != nil {
+        return x509Source, nil
+    }
     
     // SPIRE Workload API를 통해 X509 소스 생성
     source, err := workloadapi.NewX509Source(
-        ctx,
+        context.Background(),
         workloadapi.WithClientOptions(
             workloadapi.WithAddr(cfg.SocketPath),
         ),
@@ -38,16 +65,27 @@ func LoadTLSConfig(cfg *TLSConfig, logger *zap.Logger) (*tls.Config, error) {
     }
     
     x509Source = source
+    return source, nil
+}
+
+// ClientCertificateFunc - X.509 SVID를 클라이언트 인증서로 제출하는 tls.Config.GetClientCertificate
+// 핸드셰이크마다 현재 SVID를 쓰므로 SPIRE가 갱신한 인증서가 재연결 시 바로 반영됨
+// TLS_ENABLED와 무관하게 SPIRE_SOCKET_PATH의 에이전트에 연결
+func ClientCertificateFunc(cfg *TLSConfig, logger *zap.Logger) (func(*tls.CertificateRequestInfo) (*tls.Certificate, error), error) {
+    source, err := getX509Source(cfg)
+    if err != nil {
+        return nil, err
+    }
     
-    // mTLS 서버 설정 생성
-    tlsConfig := tlsconfig.MTLSServerConfig(source, source, tlsconfig.AuthorizeAny())
-    tlsConfig.MinVersion = tls.VersionTLS12
-    
-    logger.Info("SPIRE TLS configuration loaded",
-        zap.String("socket_path", cfg.SocketPath),
-        zap.Bool("mtls_enabled", true))
+    svid, err := source.GetX509SVID()
+    if err != nil {
+        return nil, fmt.Errorf("unable to get X509 SVID: %w", err)
+    }
+    logger.Info("Using SPIFFE SVID as client certificate",
+        zap.String("spiffe_id", svid.ID.String()),
+        zap.Time("expiry", svid.Certificates[0].NotAfter))
     
-    return tlsConfig, nil
+    return tlsconfig.GetClientCertificate(source), nil
 }
 
 func WatchCertificates(cfg *TLSConfig, reloadFunc func(*tls.Config) error, logger *zap.Logger) {